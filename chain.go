@@ -0,0 +1,126 @@
+package onestrike
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/AscendingHeavens/onestrike/v2/middleware"
+	"github.com/AscendingHeavens/onestrike/v2/server"
+)
+
+// routeEntry is a route as registered by the application: the bare handler,
+// the group it belongs to, if any, and its own options. Its middleware chain
+// is composed once, when the server starts serving, so middleware registered
+// with Use after the route still applies to it.
+type routeEntry struct {
+	server    *Server
+	group     *Group
+	own       []middleware.Middleware // registered with WithMiddleware
+	handler   server.HandlerFunc
+	bodyLimit int64
+	timeout   time.Duration
+	composed  server.HandlerFunc
+}
+
+// routeKey identifies a registered route within one of the server's routers.
+type routeKey struct {
+	router *server.Router
+	method string
+	path   string
+}
+
+// middlewares returns the route's full middleware stack, outermost first:
+// that of its group and the group's ancestors, then the global middleware,
+// then the route's own. Group middleware runs before the global middleware,
+// as it always has.
+func (e *routeEntry) middlewares() []middleware.Middleware {
+	var stack []middleware.Middleware
+	if e.group != nil {
+		stack = e.group.middlewares()
+	}
+	stack = append(slices.Clip(stack), e.server.middlewares...)
+	return append(slices.Clip(stack), e.own...)
+}
+
+// compose wraps the handler with the route's limits and then its current
+// middleware stack, so limits only constrain the handler itself and their
+// responses still go through logging and the like.
+func (e *routeEntry) compose() {
+	h := e.handler
+	if e.timeout > 0 {
+		h = timeoutHandler(h, e.timeout)
+	}
+	if e.bodyLimit > 0 {
+		h = bodyLimitHandler(h, e.bodyLimit)
+	}
+	e.composed = applyMiddleware(h, e.middlewares())
+}
+
+// bodyLimitHandler rejects requests whose body is known to exceed limit and
+// caps how much of the body next can read.
+func bodyLimitHandler(next server.HandlerFunc, limit int64) server.HandlerFunc {
+	return func(c *server.Context) *server.Response {
+		if c.Request.ContentLength > limit {
+			return c.ErrorJSON("Request body too large", nil, http.StatusRequestEntityTooLarge)
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		return next(c)
+	}
+}
+
+// timeoutHandler runs next with a request context that expires after d. If
+// next returns after the deadline without writing a response, it answers 503.
+func timeoutHandler(next server.HandlerFunc, d time.Duration) server.HandlerFunc {
+	return func(c *server.Context) *server.Response {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		r := c.Request
+		c.Request = r.WithContext(ctx)
+		resp := next(c)
+		c.Request = r
+
+		if !c.Handled && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return c.ErrorJSON("Request timed out", nil, http.StatusServiceUnavailable)
+		}
+		return resp
+	}
+}
+
+// serve is the handler registered in the router. It composes every route's
+// chain on first use, in case the request did not come through ServeHTTP.
+func (e *routeEntry) serve(c *server.Context) *server.Response {
+	e.server.compose()
+	return e.composed(c)
+}
+
+// compose builds the middleware chain of every registered route, once. It
+// runs when the server starts, or on the first request it serves. Routes
+// registered afterwards are composed as soon as they are added.
+func (s *Server) compose() {
+	s.composeOnce.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, e := range s.routes {
+			e.compose()
+		}
+		s.serving.Store(true)
+	})
+}
+
+// acceptMiddleware reports whether mw may still be registered. Once the
+// route chains have been composed, requests read the middleware without
+// locking, so it is refused with a warning. s.mu must be held.
+func (s *Server) acceptMiddleware(mw middleware.Middleware) bool {
+	if s.serving.Load() {
+		log.Printf("onestrike: middleware %s registered after the server started serving; it is ignored", middleware.Name(mw))
+		return false
+	}
+	return true
+}
@@ -2,8 +2,11 @@ package onestrike
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/AscendingHeavens/onestrike/v2/middleware"
+	"github.com/AscendingHeavens/onestrike/v2/server"
 )
 
 // Group represents a collection of routes sharing a common prefix
@@ -17,37 +20,168 @@ func (s *Server) Group(prefix string) *Group {
 	}
 }
 
+// target returns the router the group registers its routes into.
+func (g *Group) target() *server.Router {
+	if g.router != nil {
+		return g.router
+	}
+	return g.Server.router
+}
+
+// Group creates a nested group. Its prefix is appended to the parent's
+// prefix, and its routes run behind the parent's middleware (including
+// middleware added to the parent later), followed by its own.
+// Example: admin := v1.Group("/admin") // routes under /api/v1/admin
+func (g *Group) Group(prefix string) *Group {
+	return &Group{
+		Prefix:      g.Prefix + prefix,
+		Server:      g.Server,
+		Middlewares: make([]middleware.Middleware, 0),
+		host:        g.host,
+		router:      g.router,
+		parent:      g,
+	}
+}
+
+// Route creates a nested group for prefix and calls fn with it, which keeps
+// blocks of related routes together:
+//
+//	v1.Route("/users", func(users *onestrike.Group) {
+//		users.GET("", ListUsers)
+//		users.GET("/:id", ShowUser)
+//	})
+func (g *Group) Route(prefix string, fn func(*Group)) *Group {
+	child := g.Group(prefix)
+	fn(child)
+	return child
+}
+
+// Route creates a group for prefix and calls fn with it. See Group.Route.
+func (s *Server) Route(prefix string, fn func(*Group)) *Group {
+	group := s.Group(prefix)
+	fn(group)
+	return group
+}
+
 // Use registers a middleware for this specific group.
-// These middlewares are applied only to routes within the group,
-// in addition to any global middleware from the parent server.
+// These middlewares are applied only to routes within the group and its
+// nested groups, in addition to any global middleware from the parent server.
+// Like Server.Use, it is ignored once the server is serving.
 func (g *Group) Use(mw middleware.Middleware) {
-	g.Middlewares = append(g.Middlewares, mw)
+	g.Server.mu.Lock()
+	defer g.Server.mu.Unlock()
+	if g.Server.acceptMiddleware(mw) {
+		g.Middlewares = append(g.Middlewares, mw)
+	}
+}
+
+// UseIf registers a conditional middleware for this group. The pattern is
+// relative to the group's prefix, so on a "/api" group, UseIf("/admin/*", mw)
+// only runs mw for requests under "/api/admin/", and UseIf("!/health", mw)
+// runs it for every route of the group but "/api/health". Like Use, it is
+// inherited by nested groups. See middleware.Path for the pattern syntax.
+func (g *Group) UseIf(pattern string, mw middleware.Middleware) {
+	g.Use(middleware.Conditional(middleware.ConditionalMiddleware{
+		Pattern:    prefixPattern(g.Prefix, pattern),
+		Middleware: mw,
+	}))
+}
+
+// UseWhen registers a middleware that only runs for the group's requests
+// satisfying cond. Like Use, it is inherited by nested groups.
+func (g *Group) UseWhen(cond middleware.Condition, mw middleware.Middleware) {
+	g.Use(middleware.Conditional(middleware.ConditionalMiddleware{
+		Middleware: mw,
+		When:       cond,
+	}))
+}
+
+// prefixPattern prepends prefix to the path of a conditional pattern,
+// keeping any leading "!" and method list in front.
+func prefixPattern(prefix, pattern string) string {
+	var head string
+	if strings.HasPrefix(pattern, "!") {
+		head, pattern = "!", pattern[1:]
+	}
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		head += pattern[:i+1]
+		pattern = strings.TrimLeft(pattern[i+1:], " ")
+	}
+	return head + prefix + pattern
+}
+
+// middlewares returns the group's middleware stack: that of every ancestor
+// group, outermost first, followed by its own.
+func (g *Group) middlewares() []middleware.Middleware {
+	if g.parent == nil {
+		return g.Middlewares
+	}
+	return append(slices.Clip(g.parent.middlewares()), g.Middlewares...)
 }
 
 // Handle registers a route for the group with a specific HTTP method and path.
 // It automatically prepends the group's prefix to the path and applies
 // the group's middleware stack in reverse order for correct execution.
-func (g *Group) Handle(method, path string, handler HandlerFunc) {
-	fullPath := g.Prefix + path
+// Group-specific middlewares run around the server-level ones, and those
+// given with WithMiddleware inside both; they are composed when the server
+// starts, so Use may be called after Handle.
+func (g *Group) Handle(method, path string, handler HandlerFunc, opts ...RouteOption) {
+	cfg := newRouteConfig(opts)
+	g.Server.addRoute(g.target(), g.route(cfg, method, path), handler, g, cfg.middlewares)
+}
 
-	combined := handler
+// Remove unregisters the group's route for method and path, relative to the
+// group's prefix. See Server.Remove.
+func (g *Group) Remove(method, path string) error {
+	return g.Server.removeRoute(g.target(), method, g.Prefix+path)
+}
 
-	// Apply server-level middlewares first
-	for i := len(g.Server.middlewares) - 1; i >= 0; i-- {
-		combined = g.Server.middlewares[i](combined)
-	}
+// Replace swaps the group's route for method and path, relative to the
+// group's prefix, for one served by handler with opts. See Server.Replace.
+func (g *Group) Replace(method, path string, handler HandlerFunc, opts ...RouteOption) error {
+	cfg := newRouteConfig(opts)
+	return g.Server.replaceRoute(g.target(), g.route(cfg, method, path), handler, g, cfg.middlewares)
+}
 
-	// Then group-specific middlewares
-	for i := len(g.Middlewares) - 1; i >= 0; i-- {
-		combined = g.Middlewares[i](combined)
+// route returns the description of the group's route for method and path.
+func (g *Group) route(cfg routeConfig, method, path string) server.Route {
+	rt := cfg.route(method, g.Prefix+path)
+	rt.Prefix = g.Prefix
+	rt.Host = g.host
+	return rt
+}
+
+// Match registers the same handler for each of the given HTTP methods.
+func (g *Group) Match(methods []string, path string, handler HandlerFunc, opts ...RouteOption) {
+	for _, m := range methods {
+		g.Handle(m, path, handler, opts...)
 	}
+}
 
-	g.Server.router.Handle(method, fullPath, combined)
+// Any registers the handler for every standard HTTP method.
+func (g *Group) Any(path string, handler HandlerFunc, opts ...RouteOption) {
+	g.Match(anyMethods, path, handler, opts...)
 }
 
 // Convenience methods for common HTTP methods for group routes.
-func (g *Group) GET(path string, handler HandlerFunc)    { g.Handle(http.MethodGet, path, handler) }
-func (g *Group) POST(path string, handler HandlerFunc)   { g.Handle(http.MethodPost, path, handler) }
-func (g *Group) PUT(path string, handler HandlerFunc)    { g.Handle(http.MethodPut, path, handler) }
-func (g *Group) PATCH(path string, handler HandlerFunc)  { g.Handle(http.MethodPatch, path, handler) }
-func (g *Group) DELETE(path string, handler HandlerFunc) { g.Handle(http.MethodDelete, path, handler) }
+func (g *Group) GET(path string, handler HandlerFunc, opts ...RouteOption) {
+	g.Handle(http.MethodGet, path, handler, opts...)
+}
+func (g *Group) POST(path string, handler HandlerFunc, opts ...RouteOption) {
+	g.Handle(http.MethodPost, path, handler, opts...)
+}
+func (g *Group) PUT(path string, handler HandlerFunc, opts ...RouteOption) {
+	g.Handle(http.MethodPut, path, handler, opts...)
+}
+func (g *Group) PATCH(path string, handler HandlerFunc, opts ...RouteOption) {
+	g.Handle(http.MethodPatch, path, handler, opts...)
+}
+func (g *Group) DELETE(path string, handler HandlerFunc, opts ...RouteOption) {
+	g.Handle(http.MethodDelete, path, handler, opts...)
+}
+func (g *Group) HEAD(path string, handler HandlerFunc, opts ...RouteOption) {
+	g.Handle(http.MethodHead, path, handler, opts...)
+}
+func (g *Group) OPTIONS(path string, handler HandlerFunc, opts ...RouteOption) {
+	g.Handle(http.MethodOptions, path, handler, opts...)
+}
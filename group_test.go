@@ -70,6 +70,10 @@ func TestGroup_HTTPMethods(t *testing.T) {
 		{"PUT", func(g *Group, p string, h server.HandlerFunc) { g.PUT(p, h) }, http.MethodPut, "/put"},
 		{"PATCH", func(g *Group, p string, h server.HandlerFunc) { g.PATCH(p, h) }, http.MethodPatch, "/patch"},
 		{"DELETE", func(g *Group, p string, h server.HandlerFunc) { g.DELETE(p, h) }, http.MethodDelete, "/delete"},
+		{"HEAD", func(g *Group, p string, h server.HandlerFunc) { g.HEAD(p, h) }, http.MethodHead, "/head"},
+		{"OPTIONS", func(g *Group, p string, h server.HandlerFunc) { g.OPTIONS(p, h) }, http.MethodOptions, "/options"},
+		{"Any", func(g *Group, p string, h server.HandlerFunc) { g.Any(p, h) }, http.MethodPut, "/any"},
+		{"Match", func(g *Group, p string, h server.HandlerFunc) { g.Match([]string{http.MethodPost}, p, h) }, http.MethodPost, "/match"},
 	}
 
 	for _, tt := range methods {
@@ -94,6 +98,10 @@ func TestGroup_HTTPMethods(t *testing.T) {
 			assert.True(t, handlerCalled, "handler should be called")
 			assert.Equal(t, 200, rec.Code)
 
+			if tt.method == http.MethodHead {
+				assert.Empty(t, rec.Body.String())
+				return
+			}
 			var resp server.Response
 			err := json.NewDecoder(rec.Body).Decode(&resp)
 			assert.NoError(t, err)
@@ -101,3 +109,145 @@ func TestGroup_HTTPMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestGroup_NamedRoutes(t *testing.T) {
+	s := New()
+	v1 := s.Group("/api/v1")
+	v1.GET("/users/:id", func(c *Context) *Response {
+		u, err := c.URL("users.show", "id", c.Param("id"))
+		assert.NoError(t, err)
+		return c.Redirect(http.StatusFound, u)
+	}, WithName("users.show"))
+
+	u, err := s.URL("users.show", "id", 42)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/users/42", u)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/7", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/api/v1/users/7", rec.Header().Get("Location"))
+
+	_, err = s.URL("missing")
+	assert.ErrorIs(t, err, server.ErrUnknownRoute)
+	assert.Contains(t, s.FuncMap(), "url")
+}
+
+func TestGroup_Nested(t *testing.T) {
+	app := New()
+
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) *Response {
+				order = append(order, name)
+				return next(c)
+			}
+		}
+	}
+
+	app.Use(mark("server"))
+	v1 := app.Group("/api/v1")
+	v1.Use(mark("v1"))
+	admin := v1.Group("/admin")
+	admin.Use(mark("admin"))
+	admin.GET("/stats", func(c *Context) *Response {
+		return &Response{Success: true, Message: "stats", Code: 200}
+	})
+
+	// Middleware added to the parent after the child's routes is inherited too
+	v1.Use(mark("v1-late"))
+	admin.GET("/users", func(c *Context) *Response {
+		return &Response{Success: true, Message: "users", Code: 200}
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/stats", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// Group middleware runs around the server's, outermost group first
+	assert.Equal(t, []string{"v1", "v1-late", "admin", "server"}, order)
+
+	order = nil
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/users", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"v1", "v1-late", "admin", "server"}, order)
+
+	routes := app.Routes()
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "/api/v1/admin", routes[0].Prefix)
+	assert.Len(t, routes[1].Middlewares, 4)
+}
+
+func TestGroup_UseIf(t *testing.T) {
+	app := New()
+	api := app.Group("/api")
+	api.UseIf("/admin/*", func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			c.Writer.Header().Set("X-Admin", "1")
+			return next(c)
+		}
+	})
+	ok := func(c *Context) *Response {
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}
+	api.GET("/public", ok)
+	api.Group("/admin").GET("/stats", ok)
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/admin/stats", nil))
+	assert.Equal(t, "1", rec.Header().Get("X-Admin"))
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/public", nil))
+	assert.Empty(t, rec.Header().Get("X-Admin"))
+}
+
+func TestGroup_UseIfExclusion(t *testing.T) {
+	app := New()
+	api := app.Group("/api")
+	api.UseIf("!/health", func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			c.Writer.Header().Set("X-Auth", "1")
+			return next(c)
+		}
+	})
+	ok := func(c *Context) *Response {
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}
+	api.GET("/health", ok)
+	api.GET("/users", ok)
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	assert.Empty(t, rec.Header().Get("X-Auth"))
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, "1", rec.Header().Get("X-Auth"))
+
+	assert.Equal(t, "!GET /api/health", prefixPattern("/api", "!GET /health"))
+}
+
+func TestGroup_Route(t *testing.T) {
+	app := New()
+	ok := func(c *Context) *Response {
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}
+
+	var users *Group
+	v1 := app.Route("/api/v1", func(v1 *Group) {
+		users = v1.Route("/users", func(g *Group) {
+			g.GET("", ok)
+			g.GET("/:id", ok)
+		})
+	})
+	assert.Equal(t, "/api/v1", v1.Prefix)
+	assert.Equal(t, "/api/v1/users", users.Prefix)
+
+	for _, path := range []string{"/api/v1/users", "/api/v1/users/42"} {
+		h, _ := app.router.FindHandler(http.MethodGet, path)
+		assert.NotNil(t, h, path)
+	}
+}
@@ -0,0 +1,89 @@
+package onestrike
+
+import (
+	"slices"
+
+	"github.com/AscendingHeavens/onestrike/v2/middleware"
+	"github.com/AscendingHeavens/onestrike/v2/server"
+)
+
+// hostRouter holds the routes registered for one Host pattern.
+type hostRouter struct {
+	pattern *server.HostPattern
+	router  *server.Router
+}
+
+// Host returns a Group whose routes only match requests whose Host header
+// matches pattern. Patterns are dot-separated labels, either literal or
+// params capturing a whole label:
+//
+//	api := app.Host("api.example.com")
+//	tenants := app.Host(":tenant.example.com")
+//	tenants.GET("/", func(c *onestrike.Context) *onestrike.Response {
+//		tenant := c.Param("tenant")
+//		...
+//	})
+//
+// Host params are exposed in Context.Params alongside path params. Requests
+// whose host matches a pattern are routed only against that pattern's routes;
+// other requests use the routes registered directly on the Server. Literal
+// patterns take priority over patterns with params. Calling Host again with
+// the same pattern returns a Group sharing the same routes.
+func (s *Server) Host(pattern string) *Group {
+	hr := s.hostRouter(pattern)
+	return &Group{
+		Prefix:      "",
+		Server:      s,
+		Middlewares: make([]middleware.Middleware, 0),
+		host:        pattern,
+		router:      hr.router,
+	}
+}
+
+// hostRouter returns the router registered for pattern, creating it if needed.
+// Literal patterns are kept ahead of patterns with params. Like routes, hosts
+// may be added while serving.
+func (s *Server) hostRouter(pattern string) *hostRouter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hosts := s.hostRouters()
+	for _, hr := range hosts {
+		if hr.pattern.String() == pattern {
+			return hr
+		}
+	}
+
+	hr := &hostRouter{pattern: server.NewHostPattern(pattern), router: server.NewRouter()}
+	pos := len(hosts)
+	if hr.pattern.IsStatic() {
+		for i, existing := range hosts {
+			if !existing.pattern.IsStatic() {
+				pos = i
+				break
+			}
+		}
+	}
+	hosts = slices.Insert(slices.Clip(hosts), pos, hr)
+	s.hosts.Store(&hosts)
+	return hr
+}
+
+// hostRouters returns the current host routers, which must not be modified.
+func (s *Server) hostRouters() []*hostRouter {
+	if hosts := s.hosts.Load(); hosts != nil {
+		return *hosts
+	}
+	return nil
+}
+
+// routerFor returns the router serving requests for host, and the params
+// captured from the host pattern, if any.
+func (s *Server) routerFor(host string) (*server.Router, map[string]string) {
+	for _, hr := range s.hostRouters() {
+		if params, ok := hr.pattern.Match(host); ok {
+			return hr.router, params
+		}
+	}
+	return s.router, nil
+}
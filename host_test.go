@@ -0,0 +1,83 @@
+package onestrike
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Host(t *testing.T) {
+	reply := func(msg string) HandlerFunc {
+		return func(c *Context) *Response {
+			return &Response{Success: true, Message: msg, Details: c.Params.Map(), Code: 200}
+		}
+	}
+
+	s := New()
+	s.GET("/status", reply("default"))
+
+	// Registered before the literal host to check literal patterns win
+	tenants := s.Host(":tenant.example.com")
+	tenants.GET("/users/:id", reply("tenant"))
+
+	api := s.Host("api.example.com")
+	api.GET("/status", reply("api"), WithName("api.status"))
+	s.Host("api.example.com").POST("/status", reply("api-post"))
+
+	tests := []struct {
+		name       string
+		method     string
+		host       string
+		target     string
+		wantCode   int
+		wantMsg    string
+		wantParams map[string]any
+	}{
+		{"literal host", http.MethodGet, "api.example.com", "/status", 200, "api", map[string]any{}},
+		{"literal host with port", http.MethodGet, "API.example.com:8080", "/status", 200, "api", map[string]any{}},
+		{"same pattern shares routes", http.MethodPost, "api.example.com", "/status", 200, "api-post", map[string]any{}},
+		{"host params", http.MethodGet, "acme.example.com", "/users/7", 200, "tenant", map[string]any{"tenant": "acme", "id": "7"}},
+		{"host routes are isolated", http.MethodGet, "acme.example.com", "/status", 404, "", nil},
+		{"unknown host uses default routes", http.MethodGet, "other.org", "/status", 200, "default", map[string]any{}},
+		{"default routes do not see host routes", http.MethodGet, "other.org", "/users/7", 404, "", nil},
+		{"405 uses the host's routes", http.MethodDelete, "api.example.com", "/status", 405, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode != 200 {
+				return
+			}
+			var resp struct {
+				Message string         `json:"message"`
+				Details map[string]any `json:"details"`
+			}
+			assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.wantMsg, resp.Message)
+			if len(tt.wantParams) > 0 {
+				assert.Equal(t, tt.wantParams, resp.Details)
+			}
+		})
+	}
+
+	u, err := s.URL("api.status")
+	assert.NoError(t, err)
+	assert.Equal(t, "/status", u)
+
+	hosts := map[string]string{}
+	for _, rt := range s.Routes() {
+		hosts[rt.Method+" "+rt.Path+" "+rt.Host] = rt.Host
+	}
+	assert.Contains(t, hosts, "GET /status ")
+	assert.Contains(t, hosts, "GET /status api.example.com")
+	assert.Contains(t, hosts, "GET /users/:id :tenant.example.com")
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AscendingHeavens/onestrike/v2/server"
+)
+
+// httpChainKey is the request context key under which WrapHTTP passes the
+// OneStrike Context through a net/http middleware.
+type httpChainKey struct{}
+
+// httpChain is the per-request state shared between both sides of WrapHTTP.
+type httpChain struct {
+	c    *server.Context
+	resp *server.Response
+	ran  bool
+}
+
+// WrapHTTP adapts a standard net/http middleware, such as those from
+// third-party packages, to a OneStrike Middleware:
+//
+//	app.Use(middleware.WrapHTTP(gziphandler.GzipHandler))
+//
+// The rest of the chain runs inside the net/http middleware with the
+// ResponseWriter and Request it passes on, so context values it adds are
+// visible through c.Request and response wrappers see the JSON Response.
+// If the net/http middleware answers the request itself without calling the
+// next handler, the returned Response reports the status code it wrote.
+func WrapHTTP(mw func(http.Handler) http.Handler) Middleware {
+	return func(next server.HandlerFunc) server.HandlerFunc {
+		h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			chain := r.Context().Value(httpChainKey{}).(*httpChain)
+			c := chain.c
+
+			origWriter, origRequest := c.Writer, c.Request
+			c.Writer, c.Request = w, r
+			defer func() { c.Writer, c.Request = origWriter, origRequest }()
+
+			chain.ran = true
+			chain.resp = next(c)
+
+			// Write the Response while still inside the wrapped writer
+			if chain.resp != nil && !c.Handled {
+				c.JSON(chain.resp.Success, chain.resp.Message, chain.resp.Details, chain.resp.Code)
+			}
+		}))
+
+		return func(c *server.Context) *server.Response {
+			chain := &httpChain{c: c}
+			origRequest := c.Request
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), httpChainKey{}, chain))
+			defer func() { c.Request = origRequest }()
+
+			resp := server.WrapHandler(h)(c)
+			if chain.ran {
+				return chain.resp
+			}
+			return resp
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AscendingHeavens/onestrike/v2/server"
+	"github.com/stretchr/testify/assert"
+)
+
+type userKey struct{}
+
+func TestWrapHTTP_PassesThrough(t *testing.T) {
+	c := newTestContext(http.MethodGet)
+	origRequest := c.Request
+
+	mw := WrapHTTP(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Wrapped", "1")
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, "ada")))
+		})
+	})
+
+	handler := mw(func(ctx *server.Context) *server.Response {
+		user, _ := ctx.Request.Context().Value(userKey{}).(string)
+		return &server.Response{Success: true, Message: "hello " + user, Code: http.StatusCreated}
+	})
+
+	resp := handler(c)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, "hello ada", resp.Message)
+	assert.True(t, c.Handled)
+	assert.Same(t, origRequest, c.Request, "request must be restored after the chain")
+
+	rec := c.Writer.(*httptest.ResponseRecorder)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-Wrapped"))
+	assert.Contains(t, rec.Body.String(), "hello ada")
+}
+
+func TestWrapHTTP_ShortCircuit(t *testing.T) {
+	c := newTestContext(http.MethodGet)
+
+	mw := WrapHTTP(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "denied", http.StatusUnauthorized)
+		})
+	})
+
+	called := false
+	resp := mw(func(ctx *server.Context) *server.Response {
+		called = true
+		return &server.Response{Success: true, Code: http.StatusOK}
+	})(c)
+
+	assert.False(t, called)
+	assert.True(t, c.Handled)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.False(t, resp.Success)
+
+	rec := c.Writer.(*httptest.ResponseRecorder)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "denied")
+}
@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AscendingHeavens/onestrike/v2/server"
+)
+
+// Condition decides whether a conditional middleware runs for a request.
+type Condition func(c *server.Context) bool
+
+// Path returns a Condition matching the request against path patterns.
+// Patterns are compiled once, when Path is called, and matched segment by
+// segment:
+//
+//   - "/api" matches "/api" and every path below it, such as "/api/v1/users",
+//     but not "/apiary": a pattern not ending in a wildcard is a prefix of
+//     whole segments, as if followed by "/**"
+//   - ":name" or "*" matches exactly one non-empty segment, e.g. "/users/:id/edit"
+//   - a trailing "*" matches the rest of the path, e.g. "/api/*" matches
+//     "/api/" and "/api/v1/users" but neither "/api" nor "/apiary"
+//   - "**" matches zero or more segments, e.g. "/**/edit" matches "/edit"
+//     and "/users/42/edit"
+//
+// A pattern may be preceded by a comma-separated list of methods and a space,
+// as in "POST,PUT /api/**", and negated with a leading "!", as in
+// "!/api/health" or "!GET /api/**". The condition holds if the request
+// matches none of the negated patterns and, if there are any, at least one
+// of the others:
+//
+//	middleware.Path("/api/**", "!/api/health")
+//
+// Path panics if a pattern does not start with '/' or uses a wildcard that is
+// not a whole segment, such as "/api*".
+func Path(patterns ...string) Condition {
+	var include, exclude []pathPattern
+	for _, p := range patterns {
+		pp := compilePattern(p)
+		if pp.negate {
+			exclude = append(exclude, pp)
+		} else {
+			include = append(include, pp)
+		}
+	}
+
+	return func(c *server.Context) bool {
+		method, path := c.Request.Method, c.Request.URL.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		for _, pp := range exclude {
+			if pp.match(method, path) {
+				return false
+			}
+		}
+		if len(include) == 0 {
+			return true
+		}
+		for _, pp := range include {
+			if pp.match(method, path) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Methods returns a Condition that holds for requests using one of methods.
+func Methods(methods ...string) Condition {
+	return func(c *server.Context) bool {
+		return slices.Contains(methods, c.Request.Method)
+	}
+}
+
+// All returns a Condition that holds when every one of conds holds.
+func All(conds ...Condition) Condition {
+	return func(c *server.Context) bool {
+		for _, cond := range conds {
+			if !cond(c) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any returns a Condition that holds when at least one of conds holds.
+func Any(conds ...Condition) Condition {
+	return func(c *server.Context) bool {
+		for _, cond := range conds {
+			if cond(c) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a Condition that holds when cond does not.
+func Not(cond Condition) Condition {
+	return func(c *server.Context) bool {
+		return !cond(c)
+	}
+}
+
+// pathPattern is a compiled pattern as accepted by Path.
+type pathPattern struct {
+	negate   bool
+	methods  []string // nil matches every method
+	segments []string
+}
+
+// compilePattern parses a pattern as accepted by Path.
+func compilePattern(pattern string) pathPattern {
+	var pp pathPattern
+	p := pattern
+	if strings.HasPrefix(p, "!") {
+		pp.negate = true
+		p = p[1:]
+	}
+	if i := strings.IndexByte(p, ' '); i >= 0 {
+		pp.methods = strings.Split(strings.ToUpper(p[:i]), ",")
+		p = strings.TrimLeft(p[i+1:], " ")
+	}
+	if !strings.HasPrefix(p, "/") {
+		panic(fmt.Sprintf("onestrike: conditional pattern %q must start with '/'", pattern))
+	}
+
+	pp.segments = strings.Split(p[1:], "/")
+	for _, seg := range pp.segments {
+		if strings.Contains(seg, "*") && seg != "*" && seg != "**" {
+			panic(fmt.Sprintf("onestrike: wildcard must be a whole segment in conditional pattern %q", pattern))
+		}
+	}
+
+	// Without a trailing wildcard, a pattern also matches the paths below it
+	if last := pp.segments[len(pp.segments)-1]; last != "*" && last != "**" {
+		for len(pp.segments) > 0 && pp.segments[len(pp.segments)-1] == "" {
+			pp.segments = pp.segments[:len(pp.segments)-1]
+		}
+		pp.segments = append(pp.segments, "**")
+	}
+	return pp
+}
+
+// match reports whether a request for method and path matches the pattern,
+// ignoring negation. path must start with '/'.
+func (pp pathPattern) match(method, path string) bool {
+	if pp.methods != nil && !slices.Contains(pp.methods, method) {
+		return false
+	}
+	return matchSegments(pp.segments, path)
+}
+
+// matchSegments matches path, consumed one "/segment" at a time, against the
+// pattern segments. It backtracks on "**" and allocates nothing.
+func matchSegments(pattern []string, path string) bool {
+	for len(pattern) > 0 {
+		seg := pattern[0]
+		if seg == "**" {
+			for {
+				if matchSegments(pattern[1:], path) {
+					return true
+				}
+				if path == "" {
+					return false
+				}
+				path = path[segmentEnd(path):]
+			}
+		}
+
+		if path == "" {
+			return false
+		}
+		if seg == "*" && len(pattern) == 1 {
+			// A trailing * swallows the rest, like a route catch-all
+			return true
+		}
+
+		end := segmentEnd(path)
+		value := path[1:end]
+		switch {
+		case seg == "*" || strings.HasPrefix(seg, ":"):
+			if value == "" {
+				return false
+			}
+		case seg != value:
+			return false
+		}
+		pattern, path = pattern[1:], path[end:]
+	}
+	return path == ""
+}
+
+// segmentEnd returns the index of the '/' starting the segment after the
+// first one in path, or len(path) if path holds a single segment.
+func segmentEnd(path string) int {
+	if i := strings.IndexByte(path[1:], '/'); i >= 0 {
+		return i + 1
+	}
+	return len(path)
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AscendingHeavens/onestrike/v2/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func newConditionContext(method, path string) *server.Context {
+	return &server.Context{Writer: httptest.NewRecorder(), Request: httptest.NewRequest(method, path, nil)}
+}
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		method   string
+		path     string
+		want     bool
+	}{
+		{[]string{"/api"}, "GET", "/api", true},
+		{[]string{"/api"}, "GET", "/api/users", true},
+		{[]string{"/api"}, "GET", "/apiary", false},
+		{[]string{"/api/"}, "GET", "/api/users", true},
+		{[]string{"/"}, "GET", "/users/42", true},
+		{[]string{"/users/:id"}, "GET", "/users/42/edit", true},
+		{[]string{"/users/:id"}, "GET", "/users", false},
+		{[]string{"/api/*"}, "GET", "/api/", true},
+		{[]string{"/api/*"}, "GET", "/api/v1/users", true},
+		{[]string{"/api/*"}, "GET", "/api", false},
+		{[]string{"/api/*"}, "GET", "/apiary", false},
+		{[]string{"/api/**"}, "GET", "/api", true},
+		{[]string{"/api/**"}, "GET", "/api/v1/users", true},
+		{[]string{"/api/**"}, "GET", "/apiary", false},
+		{[]string{"/**/edit"}, "GET", "/edit", true},
+		{[]string{"/**/edit"}, "GET", "/users/42/edit", true},
+		{[]string{"/**/edit"}, "GET", "/users/42/show", false},
+		{[]string{"/users/:id/*"}, "GET", "/users/42/posts/7", true},
+		{[]string{"/users/:id/*"}, "GET", "/users//posts", false},
+		{[]string{"/users/*/posts"}, "GET", "/users/42/posts", true},
+		{[]string{"/users/*/posts"}, "GET", "/users/42/43/posts", false},
+		{[]string{"POST,PUT /api/**"}, "PUT", "/api/users", true},
+		{[]string{"POST,PUT /api/**"}, "GET", "/api/users", false},
+		{[]string{"!/api/health"}, "GET", "/api/users", true},
+		{[]string{"!/api/health"}, "GET", "/api/health", false},
+		{[]string{"!/api/health"}, "GET", "/api/health/live", false},
+		{[]string{"/api/**", "!/api/health"}, "GET", "/api/users", true},
+		{[]string{"/api/**", "!/api/health"}, "GET", "/api/health", false},
+		{[]string{"/api/**", "!GET /api/users"}, "POST", "/api/users", true},
+		{[]string{"/api/**", "!GET /api/users"}, "GET", "/api/users", false},
+		{[]string{"/api/**", "/admin/**"}, "GET", "/admin/stats", true},
+	}
+
+	for _, tt := range tests {
+		got := Path(tt.patterns...)(newConditionContext(tt.method, tt.path))
+		assert.Equal(t, tt.want, got, "%v %s %s", tt.patterns, tt.method, tt.path)
+	}
+}
+
+func TestPath_PanicsOnMalformedPattern(t *testing.T) {
+	assert.Panics(t, func() { Path("/api*") })
+	assert.Panics(t, func() { Path("api/**") })
+	assert.Panics(t, func() { Path("GET api") })
+}
+
+func TestConditionCombinators(t *testing.T) {
+	get := newConditionContext(http.MethodGet, "/api/users")
+	post := newConditionContext(http.MethodPost, "/api/users")
+	api := Path("/api/**")
+	isPost := Methods(http.MethodPost)
+
+	assert.True(t, All(api, isPost)(post))
+	assert.False(t, All(api, isPost)(get))
+	assert.True(t, All()(get))
+	assert.True(t, Any(Not(api), isPost)(post))
+	assert.False(t, Any(Not(api), isPost)(get))
+	assert.False(t, Any()(get))
+}
+
+func TestConditionalMiddleware_Matches(t *testing.T) {
+	cm := ConditionalMiddleware{
+		Pattern: "/api/*",
+		When: func(c *server.Context) bool {
+			return c.Request.Header.Get("X-Debug") != ""
+		},
+	}.Compile()
+
+	c := newConditionContext(http.MethodGet, "/api/users")
+	assert.False(t, cm.Matches(c))
+	c.Request.Header.Set("X-Debug", "1")
+	assert.True(t, cm.Matches(c))
+
+	// Uncompiled values still match, compiling on the fly
+	raw := ConditionalMiddleware{Pattern: "/api/*"}
+	assert.True(t, raw.Matches(c))
+	assert.False(t, raw.Matches(newConditionContext(http.MethodGet, "/apiary")))
+}
@@ -63,7 +63,7 @@ func CSRFWithConfig(cfg CSRFConfig) Middleware {
 				Secure:   cfg.CookieSecure,
 				HttpOnly: cfg.CookieHTTPOnly,
 			})
-			c.Params[cfg.ContextKey] = token
+			c.Set(cfg.ContextKey, token)
 
 			return next(c)
 		}
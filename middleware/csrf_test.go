@@ -18,7 +18,6 @@ func newCSRFTContext(method string, token string) *server.Context {
 	return &server.Context{
 		Request: req,
 		Writer:  w,
-		Params:  make(map[string]string),
 	}
 }
 
@@ -53,9 +52,11 @@ func TestCSRF_SetsTokenCookie_WhenMissing(t *testing.T) {
 	assert.True(t, called, "handler must be called after token creation")
 	assert.Equal(t, http.StatusOK, resp.Code)
 
-	// Verify token is set in context
-	token := c.Params[DefaultCSRFConfig.ContextKey]
+	// Verify token is set in context, and not mixed into the path params
+	token, _ := server.GetAs[string](c, DefaultCSRFConfig.ContextKey)
 	assert.NotEmpty(t, token, "CSRF token should be generated and stored in context")
+	_, inParams := c.Params.Get(DefaultCSRFConfig.ContextKey)
+	assert.False(t, inParams)
 
 	// Verify cookie is set
 	recorder := c.Writer.(*httptest.ResponseRecorder)
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/AscendingHeavens/onestrike/v2/server"
@@ -15,7 +19,6 @@ func newTestContext(method string) *server.Context {
 	return &server.Context{
 		Request: req,
 		Writer:  w,
-		Params:  make(map[string]string),
 	}
 }
 
@@ -124,3 +127,80 @@ func TestRecovery_Panic_WhenAlreadyHandled_DoesNotWriteAgain(t *testing.T) {
 	assert.Equal(t, 200, rec.Code) // Recorder defaults to 200 if not written
 	assert.Empty(t, rec.Body.String())
 }
+
+func TestLoggerAndProfiling_LogRoutePattern(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	for _, m := range []Middleware{Logger(), ProfilingMiddleware()} {
+		buf.Reset()
+		c := newTestContext(http.MethodGet)
+		c.Request = httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		c.Route = &server.Route{Method: http.MethodGet, Path: "/users/:id"}
+
+		m(func(ctx *server.Context) *server.Response {
+			return &server.Response{Success: true, Code: http.StatusOK}
+		})(c)
+
+		assert.Contains(t, buf.String(), "/users/:id")
+		assert.NotContains(t, buf.String(), "/users/42")
+	}
+}
+
+func TestLogger_ClientGoneLogs499(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := newTestContext(http.MethodGet)
+	c.Request = c.Request.WithContext(ctx)
+
+	Logger()(func(ctx *server.Context) *server.Response {
+		return ctx.JSON(true, "ok", nil, http.StatusOK)
+	})(c)
+
+	assert.Contains(t, buf.String(), " 499 ")
+}
+
+func TestLoggerAndProfiling_NilResponse(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	for _, m := range []Middleware{Logger(), ProfilingMiddleware()} {
+		buf.Reset()
+		c := newTestContext(http.MethodGet)
+
+		var resp *server.Response
+		assert.NotPanics(t, func() {
+			resp = m(func(ctx *server.Context) *server.Response {
+				ctx.Writer.WriteHeader(http.StatusNoContent)
+				ctx.Handled = true
+				return nil
+			})(c)
+		})
+		assert.Nil(t, resp)
+		assert.Regexp(t, `(GET / | Status: )200 `, buf.String())
+	}
+}
+
+func TestLoggerAndProfiling_WrittenStatus(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	for _, m := range []Middleware{Logger(), ProfilingMiddleware()} {
+		buf.Reset()
+		c := newTestContext(http.MethodGet)
+
+		// e.g. after BindRequest has answered 400
+		m(func(ctx *server.Context) *server.Response {
+			ctx.ErrorJSON("Invalid request", nil, http.StatusBadRequest)
+			return nil
+		})(c)
+		assert.Regexp(t, `(GET / | Status: )400 `, buf.String())
+	}
+}
@@ -11,12 +11,38 @@ import (
 // Example: logging, authentication, profiling, or panic recovery.
 type Middleware func(server.HandlerFunc) server.HandlerFunc
 
-// ConditionalMiddleware pairs a middleware with a path pattern.
-// The middleware is only applied if the request path matches the pattern.
-// Patterns can use a wildcard '*' at the end to match any subpath.
+// ConditionalMiddleware pairs a middleware with a path pattern and/or a
+// predicate. The middleware is only applied if the request matches both.
+// See Path for the pattern syntax; a pattern also matches the paths below it.
 type ConditionalMiddleware struct {
-	Pattern    string     // The URL path pattern to match, e.g., "/api/v1/*"
+	Pattern    string     // The URL path pattern to match, e.g., "/api/v1/*" or "POST /users/:id/*"
 	Middleware Middleware // The middleware function to apply when the pattern matches
+	When       Condition  // Optional predicate the request must also satisfy
+
+	match Condition // Pattern and When compiled together, set by Compile
+}
+
+// Compile returns a copy of cm with its pattern compiled, so that matching a
+// request does no parsing. It panics if the pattern is malformed.
+func (cm ConditionalMiddleware) Compile() ConditionalMiddleware {
+	conds := make([]Condition, 0, 2)
+	if cm.Pattern != "" {
+		conds = append(conds, Path(cm.Pattern))
+	}
+	if cm.When != nil {
+		conds = append(conds, cm.When)
+	}
+	cm.match = All(conds...)
+	return cm
+}
+
+// Matches reports whether the middleware applies to the request in c.
+// The pattern is compiled on every call unless cm was returned by Compile.
+func (cm ConditionalMiddleware) Matches(c *server.Context) bool {
+	if cm.match == nil {
+		cm = cm.Compile()
+	}
+	return cm.match(c)
 }
 
 // CORSConfig defines allowed origins, headers, and methods.
@@ -5,6 +5,10 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
 
 	"github.com/AscendingHeavens/onestrike/v2/server"
 )
@@ -18,14 +22,30 @@ func generateCSRFToken(n int) string {
 
 // getOrCreateCSRFToken ensures a token exists in context
 func getOrCreateCSRFToken(c *server.Context, cfg CSRFConfig) string {
-	if t, ok := c.Params[cfg.ContextKey]; ok && t != "" {
+	if t, ok := server.GetAs[string](c, cfg.ContextKey); ok && t != "" {
 		return t
 	}
 	token := generateCSRFToken(32)
-	c.Params[cfg.ContextKey] = token
+	c.Set(cfg.ContextKey, token)
 	return token
 }
 
+// statusCode returns the status to log for a request: the one written through
+// the Context, else resp.Code, or server.StatusClientClosedRequest if the
+// client went away before it was sent. Handlers that return no Response and
+// wrote nothing through the Context are logged as 200.
+func statusCode(c *server.Context, resp *server.Response) int {
+	switch {
+	case c.ClientGone():
+		return server.StatusClientClosedRequest
+	case c.Status() != 0:
+		return c.Status()
+	case resp == nil:
+		return http.StatusOK
+	}
+	return resp.Code
+}
+
 // validateCSRFToken compares HMACed tokens in constant time
 func validateCSRFToken(secret []byte, serverToken, clientToken string) bool {
 	h := hmac.New(sha256.New, secret)
@@ -33,3 +53,52 @@ func validateCSRFToken(secret []byte, serverToken, clientToken string) bool {
 	expected := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
 	return hmac.Equal([]byte(expected), []byte(clientToken))
 }
+
+// Name returns a short, human readable name for mw, derived from the
+// function that built it, e.g. "middleware.Logger". It is used when listing
+// the middleware chain of registered routes.
+func Name(mw Middleware) string {
+	if mw == nil {
+		return ""
+	}
+	fn := runtime.FuncForPC(reflect.ValueOf(mw).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+
+	// "github.com/x/y/middleware.Logger.func1" -> "middleware.Logger"
+	name := fn.Name()
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	for {
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		// Drop compiler generated closure suffixes such as ".func1" or ".1"
+		last := strings.TrimPrefix(name[i+1:], "func")
+		if last == "" || strings.Trim(last, "0123456789") != "" {
+			break
+		}
+		name = name[:i]
+	}
+	return name
+}
+
+// Conditional turns a ConditionalMiddleware into a plain Middleware that only
+// runs cm.Middleware for requests matching cm, and calls the next handler
+// directly otherwise. It lets conditional middleware be part of a route's own
+// chain, e.g. for groups. The pattern is compiled once, up front.
+func Conditional(cm ConditionalMiddleware) Middleware {
+	cm = cm.Compile()
+	return func(next server.HandlerFunc) server.HandlerFunc {
+		wrapped := cm.Middleware(next)
+		return func(c *server.Context) *server.Response {
+			if cm.Matches(c) {
+				return wrapped(c)
+			}
+			return next(c)
+		}
+	}
+}
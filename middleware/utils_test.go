@@ -4,6 +4,8 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/AscendingHeavens/onestrike/v2/server"
@@ -21,7 +23,7 @@ func TestGenerateCSRFToken_ReturnsBase64StringOfCorrectLength(t *testing.T) {
 }
 
 func TestGetOrCreateCSRFToken_CreatesAndCachesToken(t *testing.T) {
-	c := &server.Context{Params: make(map[string]string)}
+	c := &server.Context{}
 	cfg := CSRFConfig{ContextKey: "csrf_token"}
 
 	// First call should create token
@@ -51,3 +53,40 @@ func TestValidateCSRFToken_MatchesCorrectHMAC(t *testing.T) {
 	assert.False(t, validateCSRFToken([]byte("wrongsecret"), serverToken, expected),
 		"different secret must fail validation")
 }
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "middleware.Logger", Name(Logger()))
+	assert.Equal(t, "middleware.Recovery", Name(Recovery()))
+	assert.Equal(t, "middleware.CORSWithConfig", Name(CORS()))
+	assert.Equal(t, "middleware.TestName", Name(func(next server.HandlerFunc) server.HandlerFunc { return next }))
+	assert.Equal(t, "", Name(nil))
+}
+
+func TestConditional(t *testing.T) {
+	mw := Conditional(ConditionalMiddleware{
+		Pattern: "/admin/*",
+		Middleware: func(next server.HandlerFunc) server.HandlerFunc {
+			return func(c *server.Context) *server.Response {
+				c.Writer.Header().Set("X-Admin", "1")
+				return next(c)
+			}
+		},
+	})
+	h := mw(func(c *server.Context) *server.Response {
+		return &server.Response{Success: true, Code: http.StatusOK}
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/admin/stats", "1"},
+		{"/public", ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		c := &server.Context{Writer: rec, Request: httptest.NewRequest(http.MethodGet, tt.path, nil)}
+		h(c)
+		assert.Equal(t, tt.want, rec.Header().Get("X-Admin"), tt.path)
+	}
+}
@@ -0,0 +1,89 @@
+package onestrike
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/AscendingHeavens/onestrike/v2/server"
+)
+
+// Mount attaches a standard http.Handler, or another *Server, under prefix.
+// Every method and every path below prefix is forwarded to h with the prefix
+// stripped from the request path, so h sees "/" for prefix itself:
+//
+//	app.Mount("/admin", adminApp)           // adminApp is another *onestrike.Server
+//	app.Mount("/metrics", promhttp.Handler())
+//
+// Mounted handlers still run behind the parent's global and conditional
+// middleware, like any other route.
+func (s *Server) Mount(prefix string, h http.Handler) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	handler := mountHandler(prefix, h)
+	for _, p := range mountPatterns(prefix) {
+		s.Any(p, handler)
+	}
+}
+
+// Mount attaches a standard http.Handler, or another *Server, under the
+// group's prefix joined with prefix. The group's middleware applies to it.
+// See Server.Mount.
+func (g *Group) Mount(prefix string, h http.Handler) {
+	handler := mountHandler(strings.TrimSuffix(g.Prefix+prefix, "/"), h)
+	for _, p := range g.mountPatterns(prefix) {
+		g.Any(p, handler)
+	}
+}
+
+// mountPatterns returns the route patterns covering prefix and everything
+// below it.
+func mountPatterns(prefix string) []string {
+	if prefix == "" {
+		return []string{"/*"}
+	}
+	return []string{prefix, prefix + "/*"}
+}
+
+// mountPatterns returns the patterns covering the group's prefix joined with
+// prefix, relative to the group's prefix. They are built from the joined
+// path, so that mounting at "" or "/" covers the group's prefix itself.
+func (g *Group) mountPatterns(prefix string) []string {
+	var patterns []string
+	for _, p := range mountPatterns(strings.TrimSuffix(g.Prefix+prefix, "/")) {
+		if rel, ok := strings.CutPrefix(p, g.Prefix); ok {
+			patterns = append(patterns, rel)
+		}
+	}
+	return patterns
+}
+
+// mountHandler adapts h to a HandlerFunc that strips prefix from the path.
+func mountHandler(prefix string, h http.Handler) server.HandlerFunc {
+	return server.WrapHandler(stripPrefix(prefix, h))
+}
+
+// stripPrefix works like http.StripPrefix but maps the prefix itself to "/"
+// instead of the empty path.
+func stripPrefix(prefix string, h http.Handler) http.Handler {
+	if prefix == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := strings.TrimPrefix(r.URL.Path, prefix)
+		rp := strings.TrimPrefix(r.URL.RawPath, prefix)
+		if p == "" {
+			p = "/"
+		}
+		if r.URL.RawPath != "" && rp == "" {
+			rp = "/"
+		}
+
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = p
+		r2.URL.RawPath = rp
+		h.ServeHTTP(w, r2)
+	})
+}
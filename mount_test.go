@@ -0,0 +1,123 @@
+package onestrike
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_MountHandler(t *testing.T) {
+	s := New()
+
+	var seen []string
+	s.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			resp := next(c)
+			seen = append(seen, c.Request.URL.Path)
+			return resp
+		}
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path))
+	})
+	s.Mount("/legacy/", mux)
+
+	tests := []struct {
+		method string
+		target string
+		want   string
+	}{
+		{http.MethodGet, "/legacy", "GET /"},
+		{http.MethodGet, "/legacy/", "GET /"},
+		{http.MethodPost, "/legacy/users/42", "POST /users/42"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code, tt.target)
+		assert.Equal(t, tt.want, rec.Body.String(), tt.target)
+	}
+
+	// The parent's middleware saw the original, unstripped paths
+	assert.Equal(t, []string{"/legacy", "/legacy/", "/legacy/users/42"}, seen)
+}
+
+func TestServer_MountSubApplication(t *testing.T) {
+	admin := New()
+	admin.GET("/users/:id", func(c *Context) *Response {
+		return &Response{Success: true, Message: "admin user " + c.Param("id"), Code: 200}
+	})
+
+	s := New()
+	authed := false
+	v1 := s.Group("/api/v1")
+	v1.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			authed = true
+			return next(c)
+		}
+	})
+	v1.Mount("/admin", admin)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/7", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.True(t, authed)
+	assert.Equal(t, 200, rec.Code)
+	var resp Response
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "admin user 7", resp.Message)
+
+	// Unknown paths are answered by the sub-application
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/nope", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_MountAtRoot(t *testing.T) {
+	s := New()
+	s.GET("/ping", func(c *Context) *Response { return &Response{Success: true, Message: "pong", Code: 200} })
+	s.Mount("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("fallback " + r.URL.Path))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/anything/else", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, "fallback /anything/else", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/ping", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), "pong")
+}
+
+func TestGroup_MountAtGroupRoot(t *testing.T) {
+	for _, prefix := range []string{"", "/"} {
+		s := New()
+		s.Group("/api").Mount(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("mounted " + r.URL.Path))
+		}))
+
+		for target, want := range map[string]string{
+			"/api":          "mounted /",
+			"/api/":         "mounted /",
+			"/api/users/42": "mounted /users/42",
+		} {
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+			assert.Equal(t, http.StatusOK, rec.Code, "%q %s", prefix, target)
+			assert.Equal(t, want, rec.Body.String(), "%q %s", prefix, target)
+		}
+	}
+}
@@ -2,9 +2,12 @@ package onestrike
 
 import (
 	"encoding/json"
+	"errors"
 	"html/template"
 	"log"
 	"net/http"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/AscendingHeavens/onestrike/v2/middleware"
@@ -12,73 +15,339 @@ import (
 )
 
 // New creates a new OneStrike Server instance with an empty router and middleware stack.
+// Requests to a known path with an unregistered method are answered with 405.
 func New() *Server {
 	return &Server{
-		router:      server.NewRouter(),
-		middlewares: make([]middleware.Middleware, 0),
+		router:                 server.NewRouter(),
+		middlewares:            make([]middleware.Middleware, 0),
+		HandleMethodNotAllowed: true,
 	}
 }
 
 // Use registers a global middleware that will run on every request.
+// Middleware chains are composed when the server starts serving, so the
+// middleware also applies to routes registered before the call to Use.
+// Middleware registered once the server is serving is ignored, with a
+// warning.
 func (s *Server) Use(mw middleware.Middleware) {
-	s.middlewares = append(s.middlewares, mw)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.acceptMiddleware(mw) {
+		s.middlewares = append(s.middlewares, mw)
+	}
 }
 
 // UseIf registers a conditional middleware that only runs if the request path
-// matches the given pattern. Patterns can include a wildcard '*' at the end.
+// matches the given pattern. A pattern matches its path and every path below
+// it, so UseIf("/api", mw) covers "/api/users" but not "/apiary". Patterns can
+// include a wildcard '*' at the end, "*" or ":name" segments, "**" for any
+// number of segments, a method filter and a leading "!" to exclude paths; see
+// middleware.Path for details.
+// The pattern is compiled once, and UseIf panics if it is malformed. Like
+// Use, it is ignored once the server is serving.
 // Example: UseIf("/api/v1/*", AuthMiddleware())
+// Example: UseIf("POST,PUT /api/**", CSRF())
 func (s *Server) UseIf(pattern string, mw middleware.Middleware) {
-	s.conditionalMiddleware = append(s.conditionalMiddleware, middleware.ConditionalMiddleware{
+	s.useConditional(middleware.ConditionalMiddleware{
 		Pattern:    pattern,
 		Middleware: mw,
-	})
+	}.Compile())
+}
+
+// UseWhen registers a conditional middleware that only runs for requests
+// satisfying cond, for rules a single pattern cannot express. Like Use, it
+// is ignored once the server is serving.
+// Example: UseWhen(middleware.Path("/api/**", "!/api/health"), AuthMiddleware())
+func (s *Server) UseWhen(cond middleware.Condition, mw middleware.Middleware) {
+	s.useConditional(middleware.ConditionalMiddleware{
+		Middleware: mw,
+		When:       cond,
+	}.Compile())
+}
+
+// useConditional registers the compiled conditional middleware cm.
+func (s *Server) useConditional(cm middleware.ConditionalMiddleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.acceptMiddleware(cm.Middleware) {
+		s.conditionalMiddleware = append(s.conditionalMiddleware, cm)
+	}
+}
+
+// anyMethods is the set of HTTP methods registered by Any.
+var anyMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodConnect,
+	http.MethodTrace,
+}
+
+// applyMiddleware wraps handler with mws in reverse order, so that the first
+// middleware in the slice is the first one to run.
+func applyMiddleware(handler server.HandlerFunc, mws []middleware.Middleware) server.HandlerFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+	return handler
 }
 
 // Handle registers a route with a specific HTTP method and path.
-// Global middleware is automatically applied in reverse order (so execution order is correct).
-func (s *Server) Handle(method, path string, handler server.HandlerFunc) {
-	combined := handler
-	for i := len(s.middlewares) - 1; i >= 0; i-- {
-		combined = s.middlewares[i](combined)
+// Global middleware is automatically applied in reverse order (so execution order is correct),
+// including middleware registered with Use after the route.
+// Options such as WithName, WithMiddleware or WithMeta configure the route.
+func (s *Server) Handle(method, path string, handler server.HandlerFunc, opts ...RouteOption) {
+	cfg := newRouteConfig(opts)
+	s.addRoute(s.router, cfg.route(method, path), handler, nil, cfg.middlewares)
+}
+
+// addRoute registers rt in router, to be served by handler behind the global
+// middleware, that of group, if not nil, and the route's own mws. A conflict
+// is reported according to the server's ConflictPolicy; under ConflictError
+// it is also returned.
+func (s *Server) addRoute(router *server.Router, rt server.Route, handler server.HandlerFunc, group *Group, mws []middleware.Middleware) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.newEntry(rt, handler, group, mws)
+	if err := router.Add(rt, e.serve); err != nil {
+		if s.ConflictPolicy == ConflictPanic {
+			panic(err)
+		}
+		s.routeErrors = append(s.routeErrors, err)
+		return err
+	}
+	s.routes[routeKey{router: router, method: rt.Method, path: rt.Path}] = e
+	return nil
+}
+
+// newEntry creates the entry for a route about to be registered. Routes
+// registered once serving has begun are composed right away, before any
+// request can reach them. s.mu must be held.
+func (s *Server) newEntry(rt server.Route, handler server.HandlerFunc, group *Group, mws []middleware.Middleware) *routeEntry {
+	e := &routeEntry{server: s, group: group, own: mws, handler: handler, bodyLimit: rt.BodyLimit, timeout: rt.Timeout}
+	if s.serving.Load() {
+		e.compose()
+	}
+	if s.routes == nil {
+		s.routes = make(map[routeKey]*routeEntry)
 	}
-	s.router.Handle(method, path, combined)
+	return e
+}
+
+// Remove unregisters the route for method and pattern path, e.g.
+// app.Remove("GET", "/plugins/reports/:id"). Requests for it are answered
+// like any unmatched request from then on. Remove is safe to call while the
+// server is serving requests, and returns an error wrapping
+// server.ErrRouteNotFound if there is no such route.
+func (s *Server) Remove(method, path string) error {
+	return s.removeRoute(s.router, method, path)
+}
+
+// Replace swaps the route registered for method and pattern path for one
+// served by handler with opts, in one step: every request is served either
+// by the old route or the new one. It is safe to call while the server is
+// serving requests, and returns an error wrapping server.ErrRouteNotFound if
+// there is no such route.
+func (s *Server) Replace(method, path string, handler server.HandlerFunc, opts ...RouteOption) error {
+	cfg := newRouteConfig(opts)
+	return s.replaceRoute(s.router, cfg.route(method, path), handler, nil, cfg.middlewares)
+}
+
+// removeRoute removes the route for method and path from router.
+func (s *Server) removeRoute(router *server.Router, method, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := router.Remove(method, path); err != nil {
+		return err
+	}
+	delete(s.routes, routeKey{router: router, method: method, path: path})
+	return nil
+}
+
+// replaceRoute replaces the route for rt.Method and rt.Path in router, see
+// addRoute.
+func (s *Server) replaceRoute(router *server.Router, rt server.Route, handler server.HandlerFunc, group *Group, mws []middleware.Middleware) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.newEntry(rt, handler, group, mws)
+	if err := router.Replace(rt, e.serve); err != nil {
+		return err
+	}
+	s.routes[routeKey{router: router, method: rt.Method, path: rt.Path}] = e
+	return nil
+}
+
+// Err returns the route registration errors recorded under ConflictError,
+// joined into one error, or nil if every route was registered.
+func (s *Server) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.routeErrors...)
+}
+
+// Match registers the same handler for each of the given HTTP methods.
+// Example: app.Match([]string{"GET", "POST"}, "/login", LoginHandler)
+func (s *Server) Match(methods []string, path string, handler server.HandlerFunc, opts ...RouteOption) {
+	for _, m := range methods {
+		s.Handle(m, path, handler, opts...)
+	}
+}
+
+// Any registers the handler for every standard HTTP method.
+func (s *Server) Any(path string, handler server.HandlerFunc, opts ...RouteOption) {
+	s.Match(anyMethods, path, handler, opts...)
 }
 
 // Convenience methods for each HTTP method.
-func (s *Server) GET(path string, handler server.HandlerFunc) {
-	s.Handle(http.MethodGet, path, handler)
+func (s *Server) GET(path string, handler server.HandlerFunc, opts ...RouteOption) {
+	s.Handle(http.MethodGet, path, handler, opts...)
+}
+func (s *Server) POST(path string, handler server.HandlerFunc, opts ...RouteOption) {
+	s.Handle(http.MethodPost, path, handler, opts...)
 }
-func (s *Server) POST(path string, handler server.HandlerFunc) {
-	s.Handle(http.MethodPost, path, handler)
+func (s *Server) PUT(path string, handler server.HandlerFunc, opts ...RouteOption) {
+	s.Handle(http.MethodPut, path, handler, opts...)
 }
-func (s *Server) PUT(path string, handler server.HandlerFunc) {
-	s.Handle(http.MethodPut, path, handler)
+func (s *Server) PATCH(path string, handler server.HandlerFunc, opts ...RouteOption) {
+	s.Handle(http.MethodPatch, path, handler, opts...)
 }
-func (s *Server) PATCH(path string, handler server.HandlerFunc) {
-	s.Handle(http.MethodPatch, path, handler)
+func (s *Server) DELETE(path string, handler server.HandlerFunc, opts ...RouteOption) {
+	s.Handle(http.MethodDelete, path, handler, opts...)
 }
-func (s *Server) DELETE(path string, handler server.HandlerFunc) {
-	s.Handle(http.MethodDelete, path, handler)
+func (s *Server) HEAD(path string, handler server.HandlerFunc, opts ...RouteOption) {
+	s.Handle(http.MethodHead, path, handler, opts...)
+}
+func (s *Server) OPTIONS(path string, handler server.HandlerFunc, opts ...RouteOption) {
+	s.Handle(http.MethodOptions, path, handler, opts...)
+}
+
+// unmatchedHandler picks the handler for a request that matched no route:
+// a redirect to the canonical path if one of the redirect options applies,
+// then MethodNotAllowed if another method matches the path, then NotFound.
+func (s *Server) unmatchedHandler(router *server.Router, w http.ResponseWriter, r *http.Request) server.HandlerFunc {
+	if p, ok := s.fixedPath(router, r.Method, r.URL.Path); ok {
+		return redirectHandler(p)
+	}
+	if s.HandleMethodNotAllowed {
+		if allowed := allowedMethods(router, r.URL.Path); len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			return s.methodNotAllowedHandler()
+		}
+	}
+	return s.notFoundHandler()
+}
+
+// notFoundHandler returns the configured NotFound handler, or the default one
+// answering with a 404 JSON Response.
+func (s *Server) notFoundHandler() server.HandlerFunc {
+	if s.NotFound != nil {
+		return s.NotFound
+	}
+	return func(c *server.Context) *server.Response {
+		return c.ErrorJSON("Not Found", nil, http.StatusNotFound)
+	}
+}
+
+// methodNotAllowedHandler returns the configured MethodNotAllowed handler, or
+// the default one answering with a 405 JSON Response. The Allow header is
+// already set by the time either of them runs.
+func (s *Server) methodNotAllowedHandler() server.HandlerFunc {
+	if s.MethodNotAllowed != nil {
+		return s.MethodNotAllowed
+	}
+	return func(c *server.Context) *server.Response {
+		return c.ErrorJSON("Method Not Allowed", nil, http.StatusMethodNotAllowed)
+	}
+}
+
+// allowedMethods returns the sorted methods router can serve for path,
+// including HEAD and OPTIONS when the server answers them automatically.
+// It returns nil if no route matches the path.
+func allowedMethods(router *server.Router, path string) []string {
+	allowed := router.AllowedMethods(path)
+	if len(allowed) == 0 {
+		return nil
+	}
+	if slices.Contains(allowed, http.MethodGet) && !slices.Contains(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	if !slices.Contains(allowed, http.MethodOptions) {
+		allowed = append(allowed, http.MethodOptions)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// optionsHandler answers an OPTIONS request for a path that has no explicit
+// OPTIONS route by listing the allowed methods. It runs behind the global
+// middleware, so a CORS middleware still gets to answer preflight requests.
+func optionsHandler(allowed []string) server.HandlerFunc {
+	return func(c *server.Context) *server.Response {
+		c.Writer.Header().Set("Allow", strings.Join(allowed, ", "))
+		c.Writer.WriteHeader(http.StatusNoContent)
+		c.Handled = true
+		return &server.Response{Success: true, Message: "Allowed methods", Code: http.StatusNoContent}
+	}
 }
 
 // ServeHTTP implements http.Handler, so OneStrike Server can be passed
 // directly to http.ListenAndServe. It finds the route, applies conditional middleware,
 // executes the handler, and writes the Response as JSON.
+// HEAD requests without a HEAD route are served by the GET handler with the
+// body discarded, and OPTIONS requests without an OPTIONS route are answered
+// with the allowed methods.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	c := &server.Context{Writer: w, Request: r}
+	s.compose()
+
+	// HEAD responses never carry a body, whichever handler serves them
+	if r.Method == http.MethodHead {
+		w = &headResponseWriter{ResponseWriter: w}
+	}
+
+	c := s.acquireContext(w, r)
+	defer s.releaseContext(c)
 
-	// Find the matching handler and path parameters
-	handler, params := s.router.FindHandler(r.Method, r.URL.Path)
+	// Pick the routes for this host, then find the handler and path parameters
+	router, hostParams := s.routerFor(r.Host)
+	route, handler, params := router.Lookup(r.Method, r.URL.Path, c.Params)
 	if handler == nil {
-		http.NotFound(w, r)
-		return
+		switch r.Method {
+		case http.MethodHead:
+			route, handler, params = router.Lookup(http.MethodGet, r.URL.Path, params)
+		case http.MethodOptions:
+			if allowed := allowedMethods(router, r.URL.Path); len(allowed) > 0 {
+				handler = applyMiddleware(optionsHandler(allowed), s.middlewares)
+			}
+		}
+	}
+
+	// Unmatched requests go through the same middleware as normal routes
+	if handler == nil {
+		handler = applyMiddleware(s.unmatchedHandler(router, w, r), s.middlewares)
+	}
+
+	// Host params sit alongside path params; path params win on a clash
+	for k, v := range hostParams {
+		if _, ok := params.Get(k); !ok {
+			params = append(params, server.Param{Key: k, Value: v})
+		}
 	}
+
 	c.Params = params
+	c.Router = router
+	c.Route = route
 
 	// Apply conditional middleware if the request path matches any pattern
 	final := handler
 	for _, cm := range s.conditionalMiddleware {
-		if strings.HasPrefix(r.URL.Path, strings.TrimSuffix(cm.Pattern, "*")) {
+		if cm.Matches(c) {
 			final = cm.Middleware(final)
 		}
 	}
@@ -86,8 +355,8 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Execute the handler
 	resp := final(c)
 
-	// Write JSON response
-	if !c.Handled && resp != nil {
+	// Write JSON response, unless the client has already gone away
+	if !c.Handled && resp != nil && !c.ClientGone() {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(resp.Code)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
@@ -97,9 +366,32 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// acquireContext returns a Context from the pool, reset for the request r
+// on w.
+func (s *Server) acquireContext(w http.ResponseWriter, r *http.Request) *server.Context {
+	c, ok := s.contexts.Get().(*server.Context)
+	if !ok {
+		c = new(server.Context)
+	}
+	c.Reset(w, r)
+	return c
+}
+
+// releaseContext returns c to the pool once its response is written. It is
+// reset first, so the pool does not keep the request alive.
+func (s *Server) releaseContext(c *server.Context) {
+	c.Reset(nil, nil)
+	s.contexts.Put(c)
+}
+
 // Start runs the HTTP server on the specified address. It logs the startup
 // and will terminate the program if ListenAndServe returns an error.
+// It also refuses to start if route registration recorded any errors.
 func (s *Server) Start(addr string) {
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+	s.compose()
 	log.Printf("Starting server on %s", addr)
 	if err := http.ListenAndServe(addr, s); err != nil {
 		log.Fatal(err)
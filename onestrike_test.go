@@ -1,11 +1,20 @@
 package onestrike
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/AscendingHeavens/onestrike/v2/middleware"
 	"github.com/AscendingHeavens/onestrike/v2/server"
 	"github.com/stretchr/testify/assert"
 )
@@ -93,6 +102,7 @@ func TestServer_HTTPMethods(t *testing.T) {
 		{"PUT", func(s *Server, p string, h server.HandlerFunc) { s.PUT(p, h) }, http.MethodPut, "/put"},
 		{"PATCH", func(s *Server, p string, h server.HandlerFunc) { s.PATCH(p, h) }, http.MethodPatch, "/patch"},
 		{"DELETE", func(s *Server, p string, h server.HandlerFunc) { s.DELETE(p, h) }, http.MethodDelete, "/delete"},
+		{"OPTIONS", func(s *Server, p string, h server.HandlerFunc) { s.OPTIONS(p, h) }, http.MethodOptions, "/options"},
 	}
 
 	for _, tt := range methods {
@@ -122,3 +132,653 @@ func TestServer_HTTPMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_MethodNotAllowed(t *testing.T) {
+	s := New()
+	handler := func(c *server.Context) *server.Response {
+		return &server.Response{Success: true, Message: "ok", Code: 200}
+	}
+	s.GET("/users/:id", handler)
+	s.DELETE("/users/:id", handler)
+	s.POST("/users", handler)
+
+	req := httptest.NewRequest(http.MethodPut, "/users/42", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "DELETE, GET, HEAD, OPTIONS", rec.Header().Get("Allow"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var resp server.Response
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "Method Not Allowed", resp.Message)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+
+	// Unknown paths are still 404
+	req = httptest.NewRequest(http.MethodPut, "/teams", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Allow"))
+}
+
+func TestServer_MethodNotAllowedDisabled(t *testing.T) {
+	s := New()
+	s.HandleMethodNotAllowed = false
+	s.GET("/users", func(c *server.Context) *server.Response {
+		return &server.Response{Success: true, Message: "ok", Code: 200}
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Allow"))
+}
+
+func TestServer_AutomaticHEAD(t *testing.T) {
+	s := New()
+	s.GET("/ping", func(c *server.Context) *server.Response {
+		c.Writer.Header().Set("X-Pong", "1")
+		return &server.Response{Success: true, Message: "pong", Code: 200}
+	})
+
+	req := httptest.NewRequest(http.MethodHead, "/ping", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-Pong"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Body.String(), "HEAD must not send a body")
+
+	// An explicit HEAD route wins over the GET fallback
+	s.HEAD("/ping", func(c *server.Context) *server.Response {
+		c.Writer.Header().Set("X-Head", "1")
+		return &server.Response{Success: true, Message: "head", Code: 200}
+	})
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, "1", rec.Header().Get("X-Head"))
+}
+
+func TestServer_HEADKeepsFlusher(t *testing.T) {
+	s := New()
+	var flushErr error
+	var isFlusher bool
+	s.GET("/stream", func(c *server.Context) *server.Response {
+		_, isFlusher = c.Writer.(http.Flusher)
+		_, _ = c.Writer.Write([]byte("chunk"))
+		flushErr = http.NewResponseController(c.Writer).Flush()
+		c.Handled = true
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/stream", nil))
+
+	assert.True(t, isFlusher)
+	assert.NoError(t, flushErr)
+	assert.True(t, rec.Flushed)
+	assert.Empty(t, rec.Body.String(), "HEAD must not send a body")
+}
+
+func TestServer_AutomaticOPTIONS(t *testing.T) {
+	s := New()
+	handler := func(c *server.Context) *server.Response {
+		return &server.Response{Success: true, Message: "ok", Code: 200}
+	}
+	s.GET("/users", handler)
+	s.POST("/users", handler)
+
+	req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST", rec.Header().Get("Allow"))
+	assert.Empty(t, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodOptions, "/unknown", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_AutomaticOPTIONS_RunsGlobalMiddleware(t *testing.T) {
+	s := New()
+	// Simulates a CORS middleware answering the preflight itself
+	s.Use(func(next server.HandlerFunc) server.HandlerFunc {
+		return func(c *server.Context) *server.Response {
+			if c.Request.Method == http.MethodOptions {
+				c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+				c.Writer.WriteHeader(http.StatusOK)
+				c.Handled = true
+				return &server.Response{Success: true, Message: "preflight", Code: http.StatusOK}
+			}
+			return next(c)
+		}
+	})
+	s.GET("/users", func(c *server.Context) *server.Response {
+		return &server.Response{Success: true, Message: "ok", Code: 200}
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Allow"))
+}
+
+func TestServer_AnyAndMatch(t *testing.T) {
+	s := New()
+	handler := func(c *server.Context) *server.Response {
+		return &server.Response{Success: true, Message: c.Request.Method, Code: 200}
+	}
+	s.Any("/any", handler)
+	s.Match([]string{http.MethodGet, http.MethodPost}, "/match", handler)
+
+	for _, m := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch, http.MethodOptions} {
+		req := httptest.NewRequest(m, "/any", nil)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		assert.Equal(t, 200, rec.Code, m)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/match", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPut, "/match", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestServer_CustomNotFoundRunsThroughMiddleware(t *testing.T) {
+	s := New()
+
+	var seen []string
+	s.Use(func(next server.HandlerFunc) server.HandlerFunc {
+		return func(c *server.Context) *server.Response {
+			seen = append(seen, "global")
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+			return next(c)
+		}
+	})
+	s.UseIf("/api/*", func(next server.HandlerFunc) server.HandlerFunc {
+		return func(c *server.Context) *server.Response {
+			seen = append(seen, "api")
+			return next(c)
+		}
+	})
+	s.NotFound = func(c *server.Context) *server.Response {
+		return &server.Response{Success: false, Message: "nothing here", Code: http.StatusNotFound}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/missing", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, []string{"api", "global"}, seen)
+
+	var resp server.Response
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "nothing here", resp.Message)
+}
+
+func TestServer_CustomMethodNotAllowed(t *testing.T) {
+	s := New()
+	globalCalled := false
+	s.Use(func(next server.HandlerFunc) server.HandlerFunc {
+		return func(c *server.Context) *server.Response {
+			globalCalled = true
+			return next(c)
+		}
+	})
+	s.GET("/users", func(c *server.Context) *server.Response {
+		return &server.Response{Success: true, Message: "ok", Code: 200}
+	})
+	s.MethodNotAllowed = func(c *server.Context) *server.Response {
+		return c.ErrorJSON("use "+c.Writer.Header().Get("Allow"), nil, http.StatusMethodNotAllowed)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.True(t, globalCalled)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	var resp server.Response
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "use GET, HEAD, OPTIONS", resp.Message)
+}
+
+func TestServer_ConflictPolicy(t *testing.T) {
+	ok := func(c *server.Context) *server.Response {
+		return &server.Response{Success: true, Message: "ok", Code: 200}
+	}
+
+	s := New()
+	s.GET("/users/:id", ok)
+	assert.Panics(t, func() { s.GET("/users/:name", ok) })
+	assert.Panics(t, func() { s.Group("/users").GET("/:id", ok) })
+	assert.NoError(t, s.Err())
+
+	s = New()
+	s.ConflictPolicy = ConflictError
+	s.GET("/users/:id", ok)
+	s.GET("/users/:name", ok)
+	s.Group("/users").GET("/:id", ok)
+
+	err := s.Err()
+	assert.ErrorIs(t, err, server.ErrRouteConflict)
+	assert.Contains(t, err.Error(), "GET /users/:name")
+	assert.Len(t, s.Routes(), 1)
+}
+
+func TestServer_UseAfterRoutes(t *testing.T) {
+	app := New()
+	app.GET("/ping", func(c *Context) *Response {
+		return &Response{Success: true, Message: "pong", Code: 200}
+	})
+	api := app.Group("/api")
+	api.GET("/users", func(c *Context) *Response {
+		return &Response{Success: true, Message: "users", Code: 200}
+	})
+
+	// Registered after the routes, still applied to them
+	app.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			c.Writer.Header().Set("X-Global", "1")
+			return next(c)
+		}
+	})
+	api.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			c.Writer.Header().Set("X-Group", "1")
+			return next(c)
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, "1", rec.Header().Get("X-Global"))
+	assert.Empty(t, rec.Header().Get("X-Group"))
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, "1", rec.Header().Get("X-Global"))
+	assert.Equal(t, "1", rec.Header().Get("X-Group"))
+
+	routes := app.Routes()
+	assert.Len(t, routes[0].Middlewares, 1)
+	assert.Len(t, routes[1].Middlewares, 2)
+}
+
+func TestServer_UseAfterServingIsIgnored(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	app := New()
+	api := app.Group("/api")
+	app.GET("/ping", func(c *Context) *Response {
+		return &Response{Success: true, Message: "pong", Code: 200}
+	})
+	app.Use(middleware.Recovery())
+	assert.Empty(t, buf.String())
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	mark := func(c *Context) *Response { return nil }
+	late := func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			c.Writer.Header().Set("X-Late", "1")
+			return next(c)
+		}
+	}
+	app.Use(middleware.Logger())
+	app.Use(late)
+	app.UseIf("/**", late)
+	app.UseWhen(func(*Context) bool { return true }, late)
+	api.Use(late)
+	assert.Contains(t, buf.String(), "middleware.Logger registered after the server started serving; it is ignored")
+	assert.Equal(t, 5, strings.Count(buf.String(), "it is ignored"))
+
+	// Routes registered from now on are composed right away, without it
+	app.GET("/late", func(c *Context) *Response {
+		return &Response{Success: true, Message: "late", Code: 200}
+	})
+	api.GET("/late", mark)
+	for _, path := range []string{"/ping", "/late", "/api/late", "/missing"} {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Empty(t, rec.Header().Get("X-Late"), path)
+	}
+	for _, rt := range app.Routes() {
+		assert.Equal(t, []string{"middleware.Recovery"}, rt.Middlewares, rt.Path)
+	}
+}
+
+func TestServer_UseWhileServing(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	app := New()
+	app.GET("/ping", func(c *Context) *Response {
+		return &Response{Success: true, Message: "pong", Code: 200}
+	})
+	noop := func(next HandlerFunc) HandlerFunc { return next }
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			app.Use(noop)
+			app.UseIf("/**", noop)
+		}
+	}()
+	wg.Wait()
+}
+
+func TestServer_UseWhenAndMethodPatterns(t *testing.T) {
+	s := New()
+	header := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) *Response {
+				c.Writer.Header().Set(name, "1")
+				return next(c)
+			}
+		}
+	}
+	s.UseIf("POST /api/**", header("X-Write"))
+	s.UseWhen(middleware.Path("/api/**", "!/api/health"), header("X-Auth"))
+	s.UseIf("/api", header("X-Api")) // a plain pattern covers the paths below it
+
+	ok := func(c *Context) *Response {
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}
+	s.GET("/api/health", ok)
+	s.GET("/api/users", ok)
+	s.POST("/api/users", ok)
+	s.GET("/apiary", ok)
+
+	tests := []struct {
+		method, path       string
+		write, authed, api bool
+	}{
+		{http.MethodGet, "/api/health", false, false, true},
+		{http.MethodGet, "/api/users", false, true, true},
+		{http.MethodPost, "/api/users", true, true, true},
+		{http.MethodGet, "/apiary", false, false, false},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		assert.Equal(t, tt.write, rec.Header().Get("X-Write") != "", "%s %s", tt.method, tt.path)
+		assert.Equal(t, tt.authed, rec.Header().Get("X-Auth") != "", "%s %s", tt.method, tt.path)
+		assert.Equal(t, tt.api, rec.Header().Get("X-Api") != "", "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestServer_ContextRoute(t *testing.T) {
+	s := New()
+	var got *server.Route
+	capture := func(c *Context) *Response {
+		got = c.Route
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}
+	s.Group("/api/v1").GET("/users/:id", capture, WithName("users.show"))
+	s.UseIf("/**", func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			// Conditional middleware sees the matched route as well
+			if c.Route != nil {
+				c.Writer.Header().Set("X-Route", c.Route.Name)
+			}
+			return next(c)
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil))
+	assert.Equal(t, "users.show", rec.Header().Get("X-Route"))
+	if assert.NotNil(t, got) {
+		assert.Equal(t, "/api/v1/users/:id", got.Path)
+		assert.Equal(t, "users.show", got.Name)
+		assert.Equal(t, "/api/v1", got.Prefix)
+	}
+
+	// HEAD is served by the GET route, and reports it
+	got = nil
+	s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodHead, "/api/v1/users/42", nil))
+	if assert.NotNil(t, got) {
+		assert.Equal(t, http.MethodGet, got.Method)
+	}
+
+	// Unmatched requests have no route
+	s.NotFound = capture
+	s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Nil(t, got)
+}
+
+func TestServer_RemoveAndReplace(t *testing.T) {
+	s := New()
+	s.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			c.Writer.Header().Set("X-Global", "1")
+			return next(c)
+		}
+	})
+	reply := func(msg string) HandlerFunc {
+		return func(c *Context) *Response {
+			return &Response{Success: true, Message: msg, Code: 200}
+		}
+	}
+	plugins := s.Group("/plugins")
+	plugins.GET("/reports", reply("v1"))
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/plugins/reports", nil))
+		return rec
+	}
+	assert.Contains(t, get().Body.String(), "v1")
+
+	// Replaced and newly added routes get the middleware chain while serving
+	assert.NoError(t, plugins.Replace(http.MethodGet, "/reports", reply("v2")))
+	rec := get()
+	assert.Contains(t, rec.Body.String(), "v2")
+	assert.Equal(t, "1", rec.Header().Get("X-Global"))
+
+	assert.NoError(t, plugins.Remove(http.MethodGet, "/reports"))
+	assert.Equal(t, http.StatusNotFound, get().Code)
+	assert.Empty(t, s.Routes())
+	assert.ErrorIs(t, s.Remove(http.MethodGet, "/plugins/reports"), server.ErrRouteNotFound)
+
+	plugins.GET("/reports", reply("v3"))
+	rec = get()
+	assert.Contains(t, rec.Body.String(), "v3")
+	assert.Equal(t, "1", rec.Header().Get("X-Global"))
+}
+
+func TestServer_ConcurrentRouteChanges(t *testing.T) {
+	s := New()
+	s.Use(middleware.Recovery())
+	ok := func(c *Context) *Response {
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}
+	s.GET("/health", ok)
+
+	var wg, serving sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		serving.Add(1)
+		go func() {
+			defer wg.Done()
+			first := true
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				rec := httptest.NewRecorder()
+				s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+				if rec.Code != http.StatusOK {
+					t.Errorf("health answered %d", rec.Code)
+					return
+				}
+				s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/plugins/1", nil))
+				s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://h1.example.com/health", nil))
+				s.Routes()
+				if first {
+					first = false
+					serving.Done()
+				}
+			}
+		}()
+	}
+
+	// Change routes only once every goroutine is serving
+	serving.Wait()
+
+	for i := 0; i < 100; i++ {
+		path := fmt.Sprintf("/plugins/%d", i%4)
+		s.Host(fmt.Sprintf("h%d.example.com", i)).GET("/health", ok)
+		s.GET(path, ok)
+		assert.NoError(t, s.Replace(http.MethodGet, path, ok))
+		assert.NoError(t, s.Remove(http.MethodGet, path))
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestServer_ClientGone(t *testing.T) {
+	app := New()
+	var gone bool
+	app.GET("/slow", func(c *Context) *Response {
+		<-c.Done() // the context passed to e.g. database calls is the request's
+		gone = c.ClientGone()
+		return &Response{Success: true, Message: "too late", Code: http.StatusOK}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	assert.True(t, gone)
+	assert.Empty(t, rec.Body.String())
+	assert.Empty(t, rec.Header().Get("Content-Type"))
+}
+
+func TestServer_ContextsAreReset(t *testing.T) {
+	app := New()
+	app.GET("/users/:id", func(c *Context) *Response {
+		_, seen := c.Get("seen")
+		c.Set("seen", true)
+		return c.JSON(true, c.Param("id"), map[string]any{"seen": seen, "params": len(c.Params)}, http.StatusOK)
+	})
+
+	for _, id := range []string{"1", "2", "3"} {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/"+id, nil))
+
+		var resp Response
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, id, resp.Message)
+		assert.Equal(t, map[string]any{"seen": false, "params": float64(1)}, resp.Details)
+	}
+}
+
+func TestServer_BackgroundWorkOutlivesContext(t *testing.T) {
+	type tenantKey struct{}
+	type job struct {
+		ctx context.Context
+		id  string
+	}
+
+	app := New()
+	jobs := make(chan job, 1)
+	app.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), tenantKey{}, "acme"))
+			return next(c)
+		}
+	})
+	app.GET("/users/:id", func(c *Context) *Response {
+		if c.Param("id") == "1" {
+			jobs <- job{ctx: context.WithoutCancel(c.Request.Context()), id: c.Param("id")}
+		}
+		return c.String(http.StatusOK, "ok")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	j := <-jobs
+	for i := 2; i < 10; i++ {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/%d", i), nil))
+	}
+
+	assert.Equal(t, "1", j.id)
+	assert.Equal(t, "acme", j.ctx.Value(tenantKey{}))
+	assert.NoError(t, j.ctx.Err())
+}
+
+// discardWriter is a ResponseWriter that keeps nothing but its headers, so
+// benchmarks measure the framework's allocations only.
+type discardWriter struct{ header http.Header }
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+func benchmarkServe(b *testing.B, pattern, path string) {
+	app := New()
+	ok := &Response{Success: true, Code: http.StatusOK}
+	app.GET(pattern, func(c *Context) *Response {
+		c.Handled = true // skip JSON encoding, which is not what is measured
+		_ = c.Param("id")
+		return ok
+	})
+	app.GET("/users/:id/posts/:post", func(c *Context) *Response { return ok })
+	app.GET("/static/other", func(c *Context) *Response { return ok })
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := &discardWriter{header: make(http.Header)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		app.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkServer_StaticRoute(b *testing.B) {
+	benchmarkServe(b, "/static/route", "/static/route")
+}
+
+func BenchmarkServer_ParamRoute(b *testing.B) {
+	benchmarkServe(b, "/users/:id", "/users/42")
+}
@@ -0,0 +1,104 @@
+package onestrike
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/AscendingHeavens/onestrike/v2/server"
+)
+
+// fixedPath returns the canonical path a client should be redirected to
+// when p itself does not match any route of router for method, according to the
+// RedirectCleanPath, RedirectTrailingSlash and RedirectCaseInsensitive
+// options. It returns false if no redirect applies.
+func (s *Server) fixedPath(router *server.Router, method, p string) (string, bool) {
+	if method == http.MethodConnect || p == "/" {
+		return "", false
+	}
+
+	candidate := p
+	if s.RedirectCleanPath {
+		candidate = cleanPath(p)
+	}
+	if candidate != p && canServe(router, method, candidate) {
+		return candidate, true
+	}
+
+	if s.RedirectTrailingSlash {
+		if toggled := toggleTrailingSlash(candidate); toggled != p && canServe(router, method, toggled) {
+			return toggled, true
+		}
+	}
+
+	if s.RedirectCaseInsensitive {
+		methods := []string{method}
+		if method == http.MethodHead {
+			methods = append(methods, http.MethodGet)
+		}
+		tries := []string{candidate}
+		if s.RedirectTrailingSlash {
+			tries = append(tries, toggleTrailingSlash(candidate))
+		}
+		for _, m := range methods {
+			for _, try := range tries {
+				if fixed, ok := router.FindCaseInsensitivePath(m, try); ok && fixed != p {
+					return fixed, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// canServe reports whether a request for method and p would be handled by a
+// route of router, including the automatic HEAD and OPTIONS handling.
+func canServe(router *server.Router, method, p string) bool {
+	if router.HasRoute(method, p) {
+		return true
+	}
+	switch method {
+	case http.MethodHead:
+		return router.HasRoute(http.MethodGet, p)
+	case http.MethodOptions:
+		return len(router.AllowedMethods(p)) > 0
+	}
+	return false
+}
+
+// redirectHandler redirects to p, keeping the original query string. GET and
+// HEAD requests get a 301; other methods get a 308 so clients repeat the
+// request with the same method and body.
+func redirectHandler(p string) server.HandlerFunc {
+	return func(c *server.Context) *server.Response {
+		code := http.StatusPermanentRedirect
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+		loc := &url.URL{Path: p, RawQuery: c.Request.URL.RawQuery}
+		return c.Redirect(code, loc.String())
+	}
+}
+
+// cleanPath returns the canonical form of p: a single leading slash, no
+// duplicate slashes and no "." or ".." elements. A trailing slash is kept.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// toggleTrailingSlash adds a trailing slash to p, or removes it if present.
+func toggleTrailingSlash(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return p[:len(p)-1]
+	}
+	return p + "/"
+}
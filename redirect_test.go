@@ -0,0 +1,84 @@
+package onestrike
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Redirects(t *testing.T) {
+	ok := func(c *Context) *Response { return &Response{Success: true, Message: "ok", Code: 200} }
+
+	s := New()
+	s.RedirectTrailingSlash = true
+	s.RedirectCleanPath = true
+	s.RedirectCaseInsensitive = true
+	s.GET("/ping", ok)
+	s.POST("/ping", ok)
+	s.GET("/docs/", ok)
+	s.GET("/users/:id", ok)
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		wantCode int
+		wantLoc  string
+	}{
+		{"exact match", http.MethodGet, "/ping", 200, ""},
+		{"remove trailing slash", http.MethodGet, "/ping/", http.StatusMovedPermanently, "/ping"},
+		{"add trailing slash", http.MethodGet, "/docs", http.StatusMovedPermanently, "/docs/"},
+		{"non-GET uses 308", http.MethodPost, "/ping/", http.StatusPermanentRedirect, "/ping"},
+		{"HEAD uses 301", http.MethodHead, "/ping/", http.StatusMovedPermanently, "/ping"},
+		{"double slash", http.MethodGet, "//ping", http.StatusMovedPermanently, "/ping"},
+		{"dot segments", http.MethodGet, "/api/v1/../../ping", http.StatusMovedPermanently, "/ping"},
+		{"clean and trailing slash", http.MethodGet, "//ping/", http.StatusMovedPermanently, "/ping"},
+		{"case insensitive", http.MethodGet, "/PING", http.StatusMovedPermanently, "/ping"},
+		{"case keeps param values", http.MethodGet, "/Users/AbC", http.StatusMovedPermanently, "/users/AbC"},
+		{"query string preserved", http.MethodGet, "/ping/?q=1", http.StatusMovedPermanently, "/ping?q=1"},
+		{"no candidate", http.MethodGet, "/pong/", http.StatusNotFound, ""},
+		{"method not registered", http.MethodDelete, "/ping/", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantLoc, rec.Header().Get("Location"))
+		})
+	}
+}
+
+func TestServer_RedirectsDisabledByDefault(t *testing.T) {
+	s := New()
+	s.GET("/ping", func(c *Context) *Response { return &Response{Success: true, Code: 200} })
+
+	for _, target := range []string{"/ping/", "//ping", "/PING"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, target)
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	tests := map[string]string{
+		"":             "/",
+		"/":            "/",
+		"ping":         "/ping",
+		"//ping":       "/ping",
+		"/a/./b/":      "/a/b/",
+		"/a/../b":      "/b",
+		"/../../a":     "/a",
+		"/a//b///c//":  "/a/b/c/",
+		"/already/ok/": "/already/ok/",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, cleanPath(in), in)
+	}
+}
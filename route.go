@@ -0,0 +1,241 @@
+package onestrike
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AscendingHeavens/onestrike/v2/middleware"
+	"github.com/AscendingHeavens/onestrike/v2/server"
+)
+
+// RouteOption configures a single route at registration time. Options are
+// passed as trailing arguments to Handle and the per-method helpers:
+//
+//	app.GET("/users/:id", ShowUser, onestrike.WithName("users.show"))
+//	app.POST("/reports", CreateReport,
+//		onestrike.WithMiddleware(AuthMiddleware()),
+//		onestrike.WithMeta("scope", "reports:write"),
+//		onestrike.WithBodyLimit(1<<20),
+//		onestrike.WithTimeout(5*time.Second),
+//	)
+type RouteOption func(*routeConfig)
+
+// routeConfig collects the options given for one route.
+type routeConfig struct {
+	name        string
+	middlewares []middleware.Middleware
+	meta        map[string]any
+	tags        []string
+	bodyLimit   int64
+	timeout     time.Duration
+}
+
+// newRouteConfig applies opts in order and returns the resulting config.
+func newRouteConfig(opts []RouteOption) routeConfig {
+	var cfg routeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// route returns the description of a route for method and path with the
+// configured options applied.
+func (cfg routeConfig) route(method, path string) server.Route {
+	return server.Route{
+		Method:    method,
+		Path:      path,
+		Name:      cfg.name,
+		Meta:      cfg.meta,
+		Tags:      cfg.tags,
+		BodyLimit: cfg.bodyLimit,
+		Timeout:   cfg.timeout,
+	}
+}
+
+// WithName names the route so URLs can be built for it with Server.URL,
+// Context.URL or the "url" template function.
+func WithName(name string) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.name = name
+	}
+}
+
+// WithMiddleware adds middleware to this route only. It runs after the
+// global and group middleware, in the order given.
+func WithMiddleware(mws ...middleware.Middleware) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.middlewares = append(cfg.middlewares, mws...)
+	}
+}
+
+// WithMeta attaches a metadata value to the route. Middleware and handlers
+// read it at request time with Context.Meta, e.g. to look up the permission
+// a route requires.
+func WithMeta(key string, value any) RouteOption {
+	return func(cfg *routeConfig) {
+		if cfg.meta == nil {
+			cfg.meta = make(map[string]any)
+		}
+		cfg.meta[key] = value
+	}
+}
+
+// WithTags tags the route. Tags are listed by Server.Routes and checked at
+// request time with Context.HasTag.
+func WithTags(tags ...string) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.tags = append(cfg.tags, tags...)
+	}
+}
+
+// WithBodyLimit caps the request body at n bytes. Requests declaring a larger
+// Content-Length are rejected with 413 before the handler runs, and reading
+// past the limit fails, which the Bind helpers also report as 413.
+func WithBodyLimit(n int64) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.bodyLimit = n
+	}
+}
+
+// WithTimeout gives the handler a deadline: the request context is canceled
+// after d, and if the handler returns after the deadline without having
+// written anything, the client gets a 503. Handlers should pass
+// c.Request.Context() to any blocking call so it stops in time.
+func WithTimeout(d time.Duration) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.timeout = d
+	}
+}
+
+// URL builds the path of the route registered under name, substituting its
+// parameters from key/value pairs. Values are path-escaped.
+// Example: app.URL("users.show", "id", 42) returns "/api/v1/users/42".
+// Routes registered through Host groups are found as well.
+func (s *Server) URL(name string, params ...any) (string, error) {
+	u, err := s.router.URL(name, params...)
+	if !errors.Is(err, server.ErrUnknownRoute) {
+		return u, err
+	}
+	for _, hr := range s.hostRouters() {
+		if u, hostErr := hr.router.URL(name, params...); !errors.Is(hostErr, server.ErrUnknownRoute) {
+			return u, hostErr
+		}
+	}
+	return "", err
+}
+
+// FuncMap returns the template functions bound to this server's routes,
+// currently "url", for use with NewTemplateRenderer:
+//
+//	renderer := onestrike.NewTemplateRenderer("views/*.html", false, app.FuncMap())
+//	// in a template: <a href="{{ url "users.show" "id" .ID }}">
+func (s *Server) FuncMap() template.FuncMap {
+	return template.FuncMap{"url": s.URL}
+}
+
+// Routes returns every registered route with its method, pattern, name,
+// group prefix, host and middleware chain. Routes registered directly on the
+// server come first, in registration order, followed by those of each Host.
+// The middleware chain is reported as it would be composed at this point, in
+// the order it runs. It includes the server's conditional middleware whose
+// pattern matches the route's pattern, labelled with the pattern, e.g.
+// "middleware.CSRF (if POST /api/**)". Patterns are matched against the
+// route's pattern, not actual paths, so one naming a specific param value,
+// such as "/users/42", is not listed for "/users/:id". Middleware registered
+// with UseWhen depends on the request and is always listed, labelled
+// "(if condition)".
+func (s *Server) Routes() []Route {
+	routes := s.routesOf(s.router)
+	for _, hr := range s.hostRouters() {
+		routes = append(routes, s.routesOf(hr.router)...)
+	}
+	return routes
+}
+
+// routesOf returns the routes of router with their middleware chains filled in.
+func (s *Server) routesOf(router *server.Router) []Route {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	routes := router.Routes()
+	for i := range routes {
+		e := s.routes[routeKey{router: router, method: routes[i].Method, path: routes[i].Path}]
+		names := s.conditionalNames(routes[i])
+		routes[i].Middlewares = append(names, middlewareNames(e.middlewares())...)
+	}
+	return routes
+}
+
+// conditionalNames returns the names of the server's conditional middleware
+// that may run for rt, outermost first: ServeHTTP wraps each one around those
+// registered before it.
+func (s *Server) conditionalNames(rt Route) []string {
+	names := make([]string, 0, len(s.conditionalMiddleware))
+	req := &http.Request{Method: rt.Method, URL: &url.URL{Path: rt.Path}}
+	for _, cm := range slices.Backward(s.conditionalMiddleware) {
+		var conds []string
+		if cm.Pattern != "" {
+			if !middleware.Path(cm.Pattern)(&server.Context{Request: req}) {
+				continue
+			}
+			conds = append(conds, cm.Pattern)
+		}
+		if cm.When != nil {
+			conds = append(conds, "condition")
+		}
+		names = append(names, fmt.Sprintf("%s (if %s)", middleware.Name(cm.Middleware), strings.Join(conds, " and ")))
+	}
+	return names
+}
+
+// RoutesHandler returns a handler that dumps the route table, useful as a
+// debug endpoint:
+//
+//	app.GET("/debug/routes", app.RoutesHandler())
+//
+// The table is returned as a JSON Response by default, or as plain text when
+// requested with "?format=text" or an "Accept: text/plain" header.
+func (s *Server) RoutesHandler() HandlerFunc {
+	return func(c *Context) *Response {
+		routes := s.Routes()
+		if c.Query("format") != "text" && !strings.Contains(c.Request.Header.Get("Accept"), "text/plain") {
+			return c.JSON(true, "Registered routes", routes, http.StatusOK)
+		}
+
+		var buf bytes.Buffer
+		tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "METHOD\tPATH\tNAME\tPREFIX\tHOST\tMIDDLEWARE")
+		for _, rt := range routes {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				rt.Method, rt.Path, orDash(rt.Name), orDash(rt.Prefix), orDash(rt.Host), orDash(strings.Join(rt.Middlewares, " > ")))
+		}
+		_ = tw.Flush()
+		return c.String(http.StatusOK, buf.String())
+	}
+}
+
+// middlewareNames returns the names of the middleware in stack, in order.
+func middlewareNames(stack []middleware.Middleware) []string {
+	names := make([]string, 0, len(stack))
+	for _, mw := range stack {
+		names = append(names, middleware.Name(mw))
+	}
+	return names
+}
+
+// orDash returns s, or "-" if s is empty, for table output.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,205 @@
+package onestrike
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AscendingHeavens/onestrike/v2/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Routes(t *testing.T) {
+	s := New()
+	s.Use(middleware.Logger())
+	ok := func(c *Context) *Response { return &Response{Success: true, Message: "ok", Code: 200} }
+
+	s.GET("/ping", ok, WithName("ping"))
+	v1 := s.Group("/api/v1")
+	v1.Use(middleware.CORS())
+	v1.POST("/users", ok)
+
+	routes := s.Routes()
+	assert.Equal(t, []Route{
+		{Method: http.MethodGet, Path: "/ping", Name: "ping", Middlewares: []string{"middleware.Logger"}},
+		{Method: http.MethodPost, Path: "/api/v1/users", Prefix: "/api/v1", Middlewares: []string{"middleware.CORSWithConfig", "middleware.Logger"}},
+	}, routes)
+
+	// Callers cannot mutate the router through the returned slice
+	routes[0].Middlewares[0] = "changed"
+	assert.Equal(t, "middleware.Logger", s.Routes()[0].Middlewares[0])
+}
+
+func TestServer_RoutesListConditionalMiddleware(t *testing.T) {
+	s := New()
+	s.Use(middleware.Logger())
+	s.UseIf("/api", middleware.Recovery())
+	s.UseIf("POST /api/users/*", middleware.CSRF())
+	s.UseWhen(middleware.Methods(http.MethodGet), middleware.ProfilingMiddleware())
+	ok := func(c *Context) *Response { return &Response{Success: true, Message: "ok", Code: 200} }
+
+	s.GET("/ping", ok)
+	s.POST("/api/users/:id", ok)
+
+	routes := s.Routes()
+	assert.Equal(t, []string{
+		"middleware.ProfilingMiddleware (if condition)",
+		"middleware.Logger",
+	}, routes[0].Middlewares)
+	assert.Equal(t, []string{
+		"middleware.ProfilingMiddleware (if condition)",
+		"middleware.CSRFWithConfig (if POST /api/users/*)",
+		"middleware.Recovery (if /api)",
+		"middleware.Logger",
+	}, routes[1].Middlewares)
+}
+
+func TestServer_RoutesHandler(t *testing.T) {
+	s := New()
+	ok := func(c *Context) *Response { return &Response{Success: true, Message: "ok", Code: 200} }
+	s.GET("/users/:id", ok, WithName("users.show"))
+	s.GET("/debug/routes", s.RoutesHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/routes", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.Equal(t, 200, rec.Code)
+	var resp struct {
+		Success bool    `json:"success"`
+		Details []Route `json:"details"`
+	}
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.True(t, resp.Success)
+	if assert.Len(t, resp.Details, 2) {
+		assert.Equal(t, "/users/:id", resp.Details[0].Path)
+		assert.Equal(t, "users.show", resp.Details[0].Name)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/debug/routes?format=text", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, "METHOD")
+	assert.Regexp(t, `GET\s+/users/:id\s+users.show\s+-\s+-`, body)
+	assert.Regexp(t, `GET\s+/debug/routes\s+-`, body)
+}
+
+func TestRouteOptions_MiddlewareAndMeta(t *testing.T) {
+	s := New()
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) *Response {
+				order = append(order, name)
+				return next(c)
+			}
+		}
+	}
+	requireScope := func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			if scope, ok := c.Meta("scope"); ok && c.Request.Header.Get("X-Scope") != scope {
+				return c.ErrorJSON("Forbidden", nil, http.StatusForbidden)
+			}
+			return next(c)
+		}
+	}
+	s.Use(mark("global"))
+	s.Use(requireScope)
+
+	ok := func(c *Context) *Response {
+		order = append(order, "handler")
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}
+	api := s.Group("/api")
+	api.Use(mark("group"))
+	api.GET("/reports", ok,
+		WithMiddleware(mark("route1"), mark("route2")),
+		WithMeta("scope", "reports:read"),
+		WithTags("reports", "internal"),
+	)
+	api.GET("/public", ok)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/reports", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	order = nil
+	req := httptest.NewRequest(http.MethodGet, "/api/reports", nil)
+	req.Header.Set("X-Scope", "reports:read")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"group", "global", "route1", "route2", "handler"}, order)
+
+	// Other routes are not affected by the options
+	order = nil
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/public", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"group", "global", "handler"}, order)
+
+	routes := s.Routes()
+	assert.Equal(t, map[string]any{"scope": "reports:read"}, routes[0].Meta)
+	assert.Equal(t, []string{"reports", "internal"}, routes[0].Tags)
+	assert.Len(t, routes[0].Middlewares, 5)
+}
+
+func TestRouteOptions_BodyLimit(t *testing.T) {
+	s := New()
+	s.POST("/upload", func(c *Context) *Response {
+		var body map[string]string
+		if err := c.BindJSON(&body); err != nil {
+			return nil
+		}
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}, WithBodyLimit(16))
+
+	tests := []struct {
+		name   string
+		body   string
+		chunk  bool
+		status int
+	}{
+		{"within limit", `{"a":"b"}`, false, http.StatusOK},
+		{"declared too large", `{"a":"a long value"}`, false, http.StatusRequestEntityTooLarge},
+		{"read too large", `{"a":"a long value"}`, true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.chunk {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestRouteOptions_Timeout(t *testing.T) {
+	s := New()
+	s.GET("/slow", func(c *Context) *Response {
+		<-c.Request.Context().Done()
+		return &Response{Success: true, Message: "too late", Code: 200}
+	}, WithTimeout(10*time.Millisecond))
+	s.GET("/fast", func(c *Context) *Response {
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}, WithTimeout(time.Second))
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+)
+
+// WrapHandler adapts a standard net/http handler to a HandlerFunc so it can be
+// registered as a route. The handler writes the response itself; the returned
+// Response only reports the status code it wrote, so logging and profiling
+// middleware keep working.
+func WrapHandler(h http.Handler) HandlerFunc {
+	return func(c *Context) *Response {
+		sw := &statusWriter{ResponseWriter: c.Writer}
+		h.ServeHTTP(sw, c.Request)
+		c.Handled = true
+
+		code := sw.status
+		if code == 0 {
+			code = http.StatusOK
+		}
+		c.status = code
+		return &Response{Success: code < http.StatusBadRequest, Message: http.StatusText(code), Code: code}
+	}
+}
+
+// statusWriter wraps an http.ResponseWriter and records the status code.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before sending it.
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write records an implicit 200 if no status was sent yet.
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush forwards to the underlying writer, so streaming handlers still work.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
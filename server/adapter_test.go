@@ -0,0 +1,61 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		wantCode    int
+		wantSuccess bool
+		wantBody    string
+	}{
+		{
+			"implicit 200",
+			func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("hi")) },
+			http.StatusOK, true, "hi",
+		},
+		{
+			"explicit status",
+			func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) },
+			http.StatusTeapot, false, "",
+		},
+		{
+			"nothing written",
+			func(w http.ResponseWriter, r *http.Request) {},
+			http.StatusOK, true, "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := &Context{Writer: rec, Request: httptest.NewRequest(http.MethodGet, "/", nil)}
+
+			resp := WrapHandler(tt.handler)(c)
+
+			assert.True(t, c.Handled)
+			assert.Equal(t, tt.wantCode, resp.Code)
+			assert.Equal(t, tt.wantSuccess, resp.Success)
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func TestWrapHandler_Flush(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := &Context{Writer: rec, Request: httptest.NewRequest(http.MethodGet, "/", nil)}
+
+	WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("chunk"))
+		assert.NoError(t, http.NewResponseController(w).Flush())
+	}))(c)
+
+	assert.True(t, rec.Flushed)
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// requestSources are the struct tags read by ShouldBindRequest, highest
+// precedence first.
+var requestSources = []string{"param", "query", "header", "cookie"}
+
+// BindRequest fills dest like ShouldBindRequest. On error it writes a 400
+// Response whose details list every value that could not be bound.
+// Example: var req ListUsers; if err := c.BindRequest(&req); err != nil { return nil }
+func (c *Context) BindRequest(dest any) error {
+	err := c.ShouldBindRequest(dest)
+	if err == nil {
+		return nil
+	}
+
+	var tooLarge *http.MaxBytesError
+	var errs InputErrors
+	if !errors.As(err, &tooLarge) && errors.As(err, &errs) {
+		c.ErrorJSON("Invalid request", errs, http.StatusBadRequest)
+	} else {
+		c.writeErrorResponse(http.StatusBadRequest, "Invalid request", err)
+	}
+	return err
+}
+
+// ShouldBindRequest fills the struct pointed to by dest from every part of
+// the request, without writing a response:
+//
+//	type UpdateUser struct {
+//		ID      int    `param:"id"`
+//		Notify  bool   `query:"notify"`
+//		Tenant  string `header:"X-Tenant"`
+//		Session string `cookie:"sid"`
+//		Name    string `json:"name" form:"name"`
+//	}
+//
+// The body, if any, is decoded first according to its Content-Type, as by
+// ShouldBind. Fields tagged param, query, header or cookie are then set from
+// the first of those sources present, in that order, overriding the body:
+// path params take precedence over the query string, the query string over
+// headers and headers over cookies. Slice fields receive every value of a
+// query parameter or header. Embedded structs are filled too.
+//
+// Absent values leave their field untouched. Every value that cannot be
+// converted to its field's type is reported at once, in the returned
+// InputErrors.
+func (c *Context) ShouldBindRequest(dest any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("destination must be a pointer to struct")
+	}
+
+	var errs InputErrors
+	if c.hasBody() {
+		if err := c.ShouldBind(dest); err != nil {
+			errs = append(errs, &InputError{Source: "body", Err: err})
+		}
+	}
+
+	c.bindFields(rv.Elem(), &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// hasBody reports whether the request carries a body to decode.
+func (c *Context) hasBody() bool {
+	r := c.Request
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+// bindFields sets the tagged fields of the struct rv from the request,
+// recording conversion failures in errs.
+func (c *Context) bindFields(rv reflect.Value, errs *InputErrors) {
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Field(i)
+		fieldType := rt.Field(i)
+
+		if fieldType.Anonymous && field.Kind() == reflect.Struct {
+			c.bindFields(field, errs)
+			continue
+		}
+		if !field.CanSet() {
+			continue
+		}
+
+		for _, source := range requestSources {
+			name := fieldType.Tag.Get(source)
+			if name == "" || name == "-" {
+				continue
+			}
+			values := c.requestValues(source, name)
+			if len(values) == 0 {
+				continue
+			}
+			if bad, err := c.setFieldValues(field, values); err != nil {
+				*errs = append(*errs, &InputError{Source: source, Name: name, Value: bad, Err: err})
+			}
+			break
+		}
+	}
+}
+
+// requestValues returns the values of name from source, or nil if it is
+// absent.
+func (c *Context) requestValues(source, name string) []string {
+	r := c.Request
+	switch source {
+	case "param":
+		if v, ok := c.Params.Get(name); ok && v != "" {
+			return []string{v}
+		}
+	case "query":
+		return r.URL.Query()[name]
+	case "header":
+		return r.Header.Values(name)
+	case "cookie":
+		if cookie, err := r.Cookie(name); err == nil {
+			return []string{cookie.Value}
+		}
+	}
+	return nil
+}
+
+// setFieldValues sets field to values, all of them for a slice and the
+// first one otherwise. On failure it returns the offending value and the
+// error in the words of Input.
+func (c *Context) setFieldValues(field reflect.Value, values []string) (string, error) {
+	if field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := c.setFieldValue(slice.Index(i), v); err != nil {
+				return v, conversionError(slice.Index(i).Kind(), err)
+			}
+		}
+		field.Set(slice)
+		return "", nil
+	}
+	if err := c.setFieldValue(field, values[0]); err != nil {
+		return values[0], conversionError(field.Kind(), err)
+	}
+	return "", nil
+}
+
+// conversionError returns the Input error for a value that could not be
+// converted to kind, or err itself for unsupported kinds.
+func conversionError(kind reflect.Kind, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return errRange
+	}
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return errInt
+	case reflect.Float32, reflect.Float64:
+		return errFloat
+	case reflect.Bool:
+		return errBool
+	default:
+		return err
+	}
+}
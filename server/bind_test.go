@@ -0,0 +1,182 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pagination struct {
+	Page int `query:"page"`
+}
+
+type bindRequest struct {
+	pagination
+	ID      int      `param:"id"`
+	Tags    []string `query:"tag"`
+	Tenant  string   `header:"X-Tenant"`
+	Session string   `cookie:"sid"`
+	Name    string   `json:"name" form:"name"`
+	Limit   uint     `query:"limit" header:"X-Limit"`
+	Owner   string   `json:"owner" param:"owner" query:"owner" header:"X-Owner" cookie:"owner"`
+	Ignored string   `query:"-"`
+}
+
+func newBindContext(method, target, contentType, body string) (*Context, *httptest.ResponseRecorder) {
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+	} else {
+		req = httptest.NewRequest(method, target, nil)
+	}
+	rec := httptest.NewRecorder()
+	return &Context{Writer: rec, Request: req}, rec
+}
+
+func TestShouldBindRequest_AllSources(t *testing.T) {
+	c, _ := newBindContext(http.MethodPut, "/users/42?page=2&tag=a&tag=b&Ignored=x", "application/json", `{"name":"Ada","owner":"body"}`)
+	c.Params = Params{{Key: "id", Value: "42"}}
+	c.Request.Header.Set("X-Tenant", "acme")
+	c.Request.Header.Set("X-Limit", "50")
+	c.Request.AddCookie(&http.Cookie{Name: "sid", Value: "s3cr3t"})
+
+	var req bindRequest
+	assert.NoError(t, c.ShouldBindRequest(&req))
+	assert.Equal(t, bindRequest{
+		pagination: pagination{Page: 2},
+		ID:         42,
+		Tags:       []string{"a", "b"},
+		Tenant:     "acme",
+		Session:    "s3cr3t",
+		Name:       "Ada",
+		Limit:      50,
+		Owner:      "body",
+	}, req)
+}
+
+func TestShouldBindRequest_Precedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(c *Context)
+		target string
+		want   string
+	}{
+		{"body only", func(c *Context) {}, "/", "body"},
+		{"cookie over body", func(c *Context) {
+			c.Request.AddCookie(&http.Cookie{Name: "owner", Value: "cookie"})
+		}, "/", "cookie"},
+		{"header over cookie", func(c *Context) {
+			c.Request.AddCookie(&http.Cookie{Name: "owner", Value: "cookie"})
+			c.Request.Header.Set("X-Owner", "header")
+		}, "/", "header"},
+		{"query over header", func(c *Context) {
+			c.Request.Header.Set("X-Owner", "header")
+		}, "/?owner=query", "query"},
+		{"param over query", func(c *Context) {
+			c.Params = Params{{Key: "owner", Value: "param"}}
+		}, "/?owner=query", "param"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newBindContext(http.MethodPost, tt.target, "application/json", `{"owner":"body"}`)
+			tt.setup(c)
+
+			var req bindRequest
+			assert.NoError(t, c.ShouldBindRequest(&req))
+			assert.Equal(t, tt.want, req.Owner)
+		})
+	}
+}
+
+func TestShouldBindRequest_Form(t *testing.T) {
+	c, _ := newBindContext(http.MethodPost, "/?page=3", "application/x-www-form-urlencoded", "name=Ada")
+
+	var req bindRequest
+	assert.NoError(t, c.ShouldBindRequest(&req))
+	assert.Equal(t, "Ada", req.Name)
+	assert.Equal(t, 3, req.Page)
+}
+
+func TestShouldBindRequest_ReportsAllErrors(t *testing.T) {
+	c, _ := newBindContext(http.MethodPost, "/?page=two&tag=a&limit=-1", "application/json", `{"name":`)
+	c.Params = Params{{Key: "id", Value: "abc"}}
+
+	var req bindRequest
+	err := c.ShouldBindRequest(&req)
+
+	var errs InputErrors
+	assert.True(t, errors.As(err, &errs))
+	if assert.Len(t, errs, 4) {
+		assert.Equal(t, "body", errs[0].Source)
+		assert.Equal(t, `query "page": must be an integer, got "two"`, errs[1].Error())
+		assert.Equal(t, `param "id": must be an integer, got "abc"`, errs[2].Error())
+		assert.Equal(t, `query "limit": must be an integer, got "-1"`, errs[3].Error())
+	}
+	assert.Equal(t, []string{"a"}, req.Tags, "valid values are still bound")
+}
+
+func TestShouldBindRequest_OutOfRange(t *testing.T) {
+	c, _ := newBindContext(http.MethodGet, "/?page=300&n=200&n=1", "", "")
+	c.Request.Header.Set("X-Ratio", "1e39")
+
+	var req struct {
+		Page  uint8   `query:"page"`
+		N     []int8  `query:"n"`
+		Ratio float32 `header:"X-Ratio"`
+	}
+	err := c.ShouldBindRequest(&req)
+
+	var errs InputErrors
+	assert.True(t, errors.As(err, &errs))
+	if assert.Len(t, errs, 3) {
+		assert.Equal(t, `query "page": is out of range, got "300"`, errs[0].Error())
+		assert.Equal(t, `query "n": is out of range, got "200"`, errs[1].Error())
+		assert.Equal(t, `header "X-Ratio": is out of range, got "1e39"`, errs[2].Error())
+	}
+	assert.Zero(t, req.Page)
+	assert.Nil(t, req.N)
+}
+
+func TestShouldBindRequest_InvalidDestination(t *testing.T) {
+	c, _ := newBindContext(http.MethodGet, "/", "", "")
+
+	var req bindRequest
+	assert.EqualError(t, c.ShouldBindRequest(req), "destination must be a pointer to struct")
+}
+
+func TestBindRequest_Writes400(t *testing.T) {
+	c, rec := newBindContext(http.MethodGet, "/?page=two", "", "")
+	c.Params = Params{{Key: "id", Value: "abc"}}
+
+	var req bindRequest
+	assert.Error(t, c.BindRequest(&req))
+	assert.True(t, c.Handled)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var body struct {
+		Message string           `json:"message"`
+		Details []map[string]any `json:"details"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Invalid request", body.Message)
+	assert.Equal(t, []map[string]any{
+		{"source": "query", "name": "page", "value": "two", "error": "must be an integer"},
+		{"source": "param", "name": "id", "value": "abc", "error": "must be an integer"},
+	}, body.Details)
+}
+
+func TestBindRequest_BodyTooLarge(t *testing.T) {
+	c, rec := newBindContext(http.MethodPost, "/", "application/json", `{"name":"`+strings.Repeat("a", 64)+`"}`)
+	c.Request.Body = http.MaxBytesReader(rec, c.Request.Body, 16)
+
+	var req bindRequest
+	assert.Error(t, c.BindRequest(&req))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
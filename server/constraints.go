@@ -0,0 +1,113 @@
+package server
+
+import (
+	"regexp"
+	"strings"
+)
+
+// paramTypes holds the named constraints that can be used inline in route
+// patterns, e.g. "/users/:id<int>". Anything else between the angle brackets
+// is compiled as a regular expression that must match the whole segment.
+var paramTypes = map[string]func(string) bool{
+	"int":   isInt,
+	"uint":  isUint,
+	"alpha": isAlpha,
+	"alnum": isAlnum,
+	"uuid":  isUUID,
+}
+
+// parseParamToken splits a param token such as ":id<int>" into its name and
+// an optional constraint. It panics if the constraint is malformed, so bad
+// patterns surface when routes are registered rather than at request time.
+func parseParamToken(token string) (string, func(string) bool) {
+	body := token[1:]
+	open := strings.IndexByte(body, '<')
+	if open < 0 {
+		return body, nil
+	}
+	if !strings.HasSuffix(body, ">") || open == len(body)-2 {
+		panic("onestrike: malformed constraint in route segment " + token)
+	}
+
+	name, expr := body[:open], body[open+1:len(body)-1]
+	if fn, ok := paramTypes[expr]; ok {
+		return name, fn
+	}
+
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		panic("onestrike: invalid constraint in route segment " + token + ": " + err.Error())
+	}
+	return name, re.MatchString
+}
+
+// isUint reports whether s is a non-empty string of ASCII digits.
+func isUint(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isInt reports whether s is an optionally signed decimal integer.
+func isInt(s string) bool {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	return isUint(s)
+}
+
+// isAlpha reports whether s consists only of ASCII letters.
+func isAlpha(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isLetter(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAlnum reports whether s consists only of ASCII letters and digits.
+func isAlnum(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isLetter(s[i]) && !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isUUID reports whether s is a canonical 8-4-4-4-12 hex UUID.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(s[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isDigit(b byte) bool  { return b >= '0' && b <= '9' }
+func isLetter(b byte) bool { return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') }
+func isHex(b byte) bool    { return isDigit(b) || (b >= 'a' && b <= 'f') || (b >= 'A' && b <= 'F') }
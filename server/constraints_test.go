@@ -0,0 +1,53 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamTypes(t *testing.T) {
+	tests := []struct {
+		typ   string
+		value string
+		want  bool
+	}{
+		{"int", "123", true},
+		{"int", "-123", true},
+		{"int", "+1", true},
+		{"int", "-", false},
+		{"int", "12a", false},
+		{"uint", "0", true},
+		{"uint", "-1", false},
+		{"uint", "", false},
+		{"alpha", "abcXYZ", true},
+		{"alpha", "abc1", false},
+		{"alnum", "abc123", true},
+		{"alnum", "abc-123", false},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"uuid", "123E4567-E89B-12D3-A456-426614174000", true},
+		{"uuid", "123e4567e89b12d3a456426614174000", false},
+		{"uuid", "123e4567-e89b-12d3-a456-42661417400g", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+"/"+tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, paramTypes[tt.typ](tt.value))
+		})
+	}
+}
+
+func TestParseParamToken(t *testing.T) {
+	name, check := parseParamToken(":id")
+	assert.Equal(t, "id", name)
+	assert.Nil(t, check)
+
+	name, check = parseParamToken(":id<int>")
+	assert.Equal(t, "id", name)
+	assert.True(t, check("10"))
+
+	name, check = parseParamToken(":code<[A-Z]{3}>")
+	assert.Equal(t, "code", name)
+	assert.True(t, check("EUR"))
+	assert.False(t, check("EURO"), "regex constraints are anchored")
+}
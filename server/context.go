@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// StatusClientClosedRequest is the non-standard status, borrowed from nginx,
+// recorded for requests whose client went away before the response was
+// written. It is never sent.
+const StatusClientClosedRequest = 499
+
+// Context implements context.Context on top of the request's context, so it
+// can be passed straight to database calls and other cancellable work:
+//
+//	rows, err := db.QueryContext(c, "SELECT ...")
+//
+// It is only valid until the handler returns, see Context.
+var _ context.Context = (*Context)(nil)
+
+// Reset prepares c to serve the request r on w, dropping everything left
+// from a previous request but keeping the storage of Params and of the
+// request-scoped values for reuse.
+func (c *Context) Reset(w http.ResponseWriter, r *http.Request) {
+	clear(c.Params)
+	clear(c.values)
+	*c = Context{Writer: w, Request: r, Params: c.Params[:0], values: c.values}
+}
+
+// Deadline returns the deadline of the request's context, e.g. the one set by
+// a route timeout.
+func (c *Context) Deadline() (time.Time, bool) {
+	return c.context().Deadline()
+}
+
+// Done returns a channel that is closed when the request is cancelled, times
+// out or its client disconnects.
+func (c *Context) Done() <-chan struct{} {
+	return c.context().Done()
+}
+
+// Err returns why the request's context is done, or nil while it is not.
+func (c *Context) Err() error {
+	return c.context().Err()
+}
+
+// Value returns the value stored with Set for a string or ContextKey key,
+// and otherwise the request's context value for key.
+func (c *Context) Value(key any) any {
+	switch k := key.(type) {
+	case string:
+		if v, ok := c.values[k]; ok {
+			return v
+		}
+	case ContextKey:
+		if v, ok := c.values[string(k)]; ok {
+			return v
+		}
+	}
+	return c.context().Value(key)
+}
+
+// ClientGone reports whether the client disconnected, or the request was
+// otherwise cancelled, before the response was complete. Responses are not
+// written once the client is gone.
+func (c *Context) ClientGone() bool {
+	return errors.Is(c.context().Err(), context.Canceled)
+}
+
+// context returns the request's context, or an empty one if there is no
+// request.
+func (c *Context) context() context.Context {
+	if c.Request == nil {
+		return context.Background()
+	}
+	return c.Request.Context()
+}
+
+// Status returns the status code written for the request by the Context's
+// helpers, such as JSON, ErrorJSON, String or Redirect, or by a wrapped
+// http.Handler, or 0 if none has been written through them. Middleware reads
+// it to log what was sent when a handler returns no Response.
+func (c *Context) Status() int {
+	return c.status
+}
+
+// writeHeader sends the status code and records it for Status.
+func (c *Context) writeHeader(code int) {
+	c.status = code
+	c.Writer.WriteHeader(code)
+}
+
+// skipWrite reports whether a response must not be written, because one
+// already was or because the client is gone. In the latter case the request
+// is marked handled, so nothing later tries either.
+func (c *Context) skipWrite() bool {
+	if !c.Handled && c.ClientGone() {
+		c.Handled = true
+	}
+	return c.Handled
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_ImplementsContext(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.WithValue(context.Background(), ContextKey("tenant"), "acme"), deadline)
+	defer cancel()
+
+	c := &Context{Request: httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)}
+	c.Set("user", "alice")
+
+	d, ok := c.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, d)
+	assert.NoError(t, c.Err())
+
+	assert.Equal(t, "alice", c.Value("user"))
+	assert.Equal(t, "alice", c.Value(ContextKey("user")))
+	assert.Equal(t, "acme", c.Value(ContextKey("tenant")))
+	assert.Nil(t, c.Value("missing"))
+
+	// Derived contexts see the same values and cancellation
+	child, stop := context.WithCancel(c)
+	defer stop()
+	assert.Equal(t, "alice", child.Value("user"))
+
+	cancel()
+	<-c.Done()
+	<-child.Done()
+	assert.ErrorIs(t, c.Err(), context.Canceled)
+}
+
+func TestContext_WithoutRequest(t *testing.T) {
+	c := &Context{}
+	_, ok := c.Deadline()
+	assert.False(t, ok)
+	assert.Nil(t, c.Done())
+	assert.NoError(t, c.Err())
+	assert.False(t, c.ClientGone())
+}
+
+func TestContext_ClientGoneSkipsWrites(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	writers := map[string]func(c *Context) *Response{
+		"String":    func(c *Context) *Response { return c.String(http.StatusOK, "hi") },
+		"JSON":      func(c *Context) *Response { return c.JSON(true, "ok", nil, http.StatusOK) },
+		"ErrorJSON": func(c *Context) *Response { return c.ErrorJSON("bad", nil, http.StatusBadRequest) },
+		"Redirect":  func(c *Context) *Response { return c.Redirect(http.StatusFound, "/x") },
+	}
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := &Context{Writer: w, Request: httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)}
+
+			assert.True(t, c.ClientGone())
+			write(c)
+
+			assert.True(t, c.Handled)
+			assert.False(t, w.Flushed)
+			assert.Empty(t, w.Body.String())
+			assert.Empty(t, w.Header())
+		})
+	}
+}
+
+func TestContext_DeadlineIsNotClientGone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+
+	w := httptest.NewRecorder()
+	c := &Context{Writer: w, Request: httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)}
+	assert.False(t, c.ClientGone())
+
+	c.ErrorJSON("Request timed out", nil, http.StatusServiceUnavailable)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+func TestContext_Reset(t *testing.T) {
+	old := httptest.NewRequest(http.MethodGet, "/old", nil)
+	c := &Context{
+		Writer:  httptest.NewRecorder(),
+		Request: old,
+		Params:  Params{{Key: "id", Value: "1"}},
+		Handled: true,
+		Route:   &Route{Path: "/old"},
+	}
+	c.Set("user", "alice")
+	params := c.Params
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/new", nil)
+	c.Reset(w, r)
+
+	assert.Same(t, w, c.Writer)
+	assert.Same(t, r, c.Request)
+	assert.False(t, c.Handled)
+	assert.Nil(t, c.Route)
+	assert.Empty(t, c.Params)
+	assert.Equal(t, cap(params), cap(c.Params), "params storage is kept")
+	_, ok := c.Get("user")
+	assert.False(t, ok)
+}
+
+func TestContext_Status(t *testing.T) {
+	c := &Context{Writer: httptest.NewRecorder(), Request: httptest.NewRequest(http.MethodGet, "/", nil)}
+	assert.Zero(t, c.Status())
+
+	c.ErrorJSON("Invalid request", nil, http.StatusBadRequest)
+	assert.Equal(t, http.StatusBadRequest, c.Status())
+
+	c.Reset(httptest.NewRecorder(), c.Request)
+	assert.Zero(t, c.Status())
+	c.Redirect(http.StatusFound, "/login")
+	assert.Equal(t, http.StatusFound, c.Status())
+
+	c.Reset(httptest.NewRecorder(), c.Request)
+	WrapHandler(http.NotFoundHandler())(c)
+	assert.Equal(t, http.StatusNotFound, c.Status())
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net"
+	"strings"
+)
+
+// HostPattern matches the Host header of a request against a pattern made of
+// dot-separated labels. Labels are either literal, e.g. "api.example.com", or
+// params that capture a whole label, e.g. ":tenant.example.com". Params accept
+// the same inline constraints as path params, e.g. ":tenant<alnum>.example.com".
+// Literal labels are compared case-insensitively and any port is ignored.
+type HostPattern struct {
+	pattern string
+	labels  []hostLabel
+	static  bool
+}
+
+// hostLabel is a single label of a HostPattern.
+type hostLabel struct {
+	value string            // literal label, lowercased
+	name  string            // param name, empty for literal labels
+	check func(string) bool // optional constraint on a param's value
+}
+
+// NewHostPattern parses pattern. It panics if a param constraint is malformed.
+func NewHostPattern(pattern string) *HostPattern {
+	h := &HostPattern{pattern: pattern, static: true}
+	for _, label := range strings.Split(strings.TrimSuffix(pattern, "."), ".") {
+		if strings.HasPrefix(label, ":") {
+			name, check := parseParamToken(label)
+			h.labels = append(h.labels, hostLabel{name: name, check: check})
+			h.static = false
+			continue
+		}
+		h.labels = append(h.labels, hostLabel{value: strings.ToLower(label)})
+	}
+	return h
+}
+
+// String returns the pattern h was created from.
+func (h *HostPattern) String() string {
+	return h.pattern
+}
+
+// IsStatic reports whether the pattern has no params. Static patterns are
+// more specific and should be tried before patterns with params.
+func (h *HostPattern) IsStatic() bool {
+	return h.static
+}
+
+// Match reports whether host (as found in http.Request.Host, with or without
+// a port) matches the pattern, and returns the captured params, if any.
+func (h *HostPattern) Match(host string) (map[string]string, bool) {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.TrimSuffix(host, ".")
+
+	var params map[string]string
+	for i, label := range h.labels {
+		var part string
+		part, host, _ = strings.Cut(host, ".")
+		if part == "" {
+			return nil, false
+		}
+		// Every label but the last must be followed by another one
+		if (host == "") != (i == len(h.labels)-1) {
+			return nil, false
+		}
+
+		if label.name == "" {
+			if !strings.EqualFold(part, label.value) {
+				return nil, false
+			}
+			continue
+		}
+		if label.check != nil && !label.check(part) {
+			return nil, false
+		}
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[label.name] = part
+	}
+	return params, true
+}
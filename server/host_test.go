@@ -0,0 +1,44 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostPattern_Match(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		host       string
+		wantMatch  bool
+		wantParams map[string]string
+	}{
+		{"api.example.com", "api.example.com", true, nil},
+		{"api.example.com", "API.Example.com:8080", true, nil},
+		{"api.example.com", "api.example.com.", true, nil},
+		{"api.example.com", "admin.example.com", false, nil},
+		{"api.example.com", "v2.api.example.com", false, nil},
+		{"api.example.com", "example.com", false, nil},
+		{":tenant.example.com", "acme.example.com", true, map[string]string{"tenant": "acme"}},
+		{":tenant.example.com", "acme.example.com:443", true, map[string]string{"tenant": "acme"}},
+		{":tenant.example.com", "example.com", false, nil},
+		{":tenant.:region.example.com", "acme.eu.example.com", true, map[string]string{"tenant": "acme", "region": "eu"}},
+		{":tenant<alpha>.example.com", "acme1.example.com", false, nil},
+		{"localhost", "[::1]:8080", false, nil},
+		{"localhost", "localhost:8080", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.host, func(t *testing.T) {
+			params, ok := NewHostPattern(tt.pattern).Match(tt.host)
+			assert.Equal(t, tt.wantMatch, ok)
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func TestHostPattern_IsStatic(t *testing.T) {
+	assert.True(t, NewHostPattern("api.example.com").IsStatic())
+	assert.False(t, NewHostPattern(":tenant.example.com").IsStatic())
+	assert.Equal(t, ":tenant.example.com", NewHostPattern(":tenant.example.com").String())
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Input reads typed values from a request's path params, query string and
+// headers. Values that are present but malformed are replaced by the default
+// and recorded, so a handler reads everything first and checks once:
+//
+//	in := c.Input()
+//	id := in.ParamInt("id")
+//	page := in.QueryInt("page", 1)
+//	since := in.QueryTime("since", time.RFC3339, time.Time{})
+//	if resp := in.Must(); resp != nil {
+//		return resp // 400 listing every invalid value
+//	}
+type Input struct {
+	c    *Context
+	errs InputErrors
+}
+
+// Input returns a reader of typed request values. See Input.
+func (c *Context) Input() *Input {
+	return &Input{c: c}
+}
+
+// InputError describes a request value that could not be read.
+type InputError struct {
+	Source string // "param", "query", "header", "cookie" or "body"
+	Name   string // empty for the body
+	Value  string
+	Err    error
+}
+
+// Error implements the error interface.
+// Example: query "page": must be an integer, got "abc"
+func (e *InputError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s: %v", e.Source, e.Err)
+	}
+	if e.Value == "" {
+		return fmt.Sprintf("%s %q: %v", e.Source, e.Name, e.Err)
+	}
+	return fmt.Sprintf("%s %q: %v, got %q", e.Source, e.Name, e.Err, e.Value)
+}
+
+// Unwrap returns the underlying error.
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the error as an object with source, name, value and
+// error fields, for the details of 400 Responses.
+func (e *InputError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Source string `json:"source"`
+		Name   string `json:"name,omitempty"`
+		Value  string `json:"value,omitempty"`
+		Error  string `json:"error"`
+	}{e.Source, e.Name, e.Value, e.Err.Error()})
+}
+
+// InputErrors lists every request value that could not be read.
+type InputErrors []*InputError
+
+// Error joins the messages of all errors with "; ".
+func (es InputErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors, so errors.Is and errors.As look into each.
+func (es InputErrors) Unwrap() []error {
+	errs := make([]error, len(es))
+	for i, e := range es {
+		errs[i] = e
+	}
+	return errs
+}
+
+var (
+	errRequired = errors.New("is required")
+	errInt      = errors.New("must be an integer")
+	errFloat    = errors.New("must be a number")
+	errBool     = errors.New("must be a boolean")
+	errUUID     = errors.New("must be a UUID")
+	errRange    = errors.New("is out of range")
+)
+
+// Err returns the InputErrors recorded so far, or nil if every value read was
+// valid.
+func (in *Input) Err() error {
+	if len(in.errs) == 0 {
+		return nil
+	}
+	return in.errs
+}
+
+// Must returns nil if every value read was valid. Otherwise it writes and
+// returns a 400 Response whose details list every invalid value.
+func (in *Input) Must() *Response {
+	if len(in.errs) == 0 {
+		return nil
+	}
+	return in.c.ErrorJSON("Invalid request parameters", in.errs, http.StatusBadRequest)
+}
+
+// fail records that the value of name from source is invalid.
+func (in *Input) fail(source, name, value string, err error) {
+	in.errs = append(in.errs, &InputError{Source: source, Name: name, Value: value, Err: err})
+}
+
+// QueryDefault returns the query parameter key, or def if it is absent or
+// empty.
+func (in *Input) QueryDefault(key, def string) string {
+	if v := in.c.Query(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// QueryInt returns the query parameter key as an int, or def if it is absent.
+func (in *Input) QueryInt(key string, def int) int {
+	return in.int("query", key, in.c.Query(key), def)
+}
+
+// QueryFloat returns the query parameter key as a float64, or def if it is
+// absent.
+func (in *Input) QueryFloat(key string, def float64) float64 {
+	v := in.c.Query(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		in.fail("query", key, v, errFloat)
+		return def
+	}
+	return f
+}
+
+// QueryBool returns the query parameter key as a bool, or def if it is
+// absent. It accepts the values of strconv.ParseBool, e.g. "1" and "true".
+func (in *Input) QueryBool(key string, def bool) bool {
+	v := in.c.Query(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		in.fail("query", key, v, errBool)
+		return def
+	}
+	return b
+}
+
+// QueryTime returns the query parameter key parsed with layout, e.g.
+// time.RFC3339 or time.DateOnly, or def if it is absent.
+func (in *Input) QueryTime(key, layout string, def time.Time) time.Time {
+	v := in.c.Query(key)
+	if v == "" {
+		return def
+	}
+	t, err := time.Parse(layout, v)
+	if err != nil {
+		in.fail("query", key, v, fmt.Errorf("must be a time formatted as %s", layout))
+		return def
+	}
+	return t
+}
+
+// ParamInt returns the path parameter name as an int. A missing param is
+// recorded as an error, like a malformed one, and 0 is returned.
+func (in *Input) ParamInt(name string) int {
+	v := in.c.Param(name)
+	if v == "" {
+		in.fail("param", name, "", errRequired)
+		return 0
+	}
+	return in.int("param", name, v, 0)
+}
+
+// ParamUUID returns the path parameter name if it is a UUID in its canonical
+// textual form, e.g. "123e4567-e89b-12d3-a456-426614174000". Otherwise the
+// param is recorded as invalid and "" is returned.
+func (in *Input) ParamUUID(name string) string {
+	v := in.c.Param(name)
+	switch {
+	case v == "":
+		in.fail("param", name, "", errRequired)
+	case !isUUID(v):
+		in.fail("param", name, v, errUUID)
+	default:
+		return v
+	}
+	return ""
+}
+
+// HeaderInt returns the request header key as an int, or def if it is absent.
+func (in *Input) HeaderInt(key string, def int) int {
+	var v string
+	if in.c.Request != nil {
+		v = in.c.Request.Header.Get(key)
+	}
+	return in.int("header", key, v, def)
+}
+
+// int parses v, the value of name from source, or returns def if v is empty.
+func (in *Input) int(source, name, v string, def int) int {
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		in.fail(source, name, v, errInt)
+		return def
+	}
+	return n
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newInputContext(target string, params Params) (*Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("X-Limit", "25")
+	rec := httptest.NewRecorder()
+	return &Context{Writer: rec, Request: req, Params: params}, rec
+}
+
+func TestInput_ValidValues(t *testing.T) {
+	const ref = "123e4567-e89b-12d3-a456-426614174000"
+	c, _ := newInputContext("/?page=3&ratio=0.5&draft=1&since=2024-05-01&q=go",
+		Params{{Key: "id", Value: "42"}, {Key: "ref", Value: ref}})
+
+	in := c.Input()
+	assert.Equal(t, 42, in.ParamInt("id"))
+	assert.Equal(t, ref, in.ParamUUID("ref"))
+	assert.Equal(t, 3, in.QueryInt("page", 1))
+	assert.Equal(t, 0.5, in.QueryFloat("ratio", 1))
+	assert.True(t, in.QueryBool("draft", false))
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), in.QueryTime("since", time.DateOnly, time.Time{}))
+	assert.Equal(t, "go", in.QueryDefault("q", "all"))
+	assert.Equal(t, 25, in.HeaderInt("X-Limit", 10))
+
+	assert.NoError(t, in.Err())
+	assert.Nil(t, in.Must())
+}
+
+func TestInput_Defaults(t *testing.T) {
+	c, _ := newInputContext("/", nil)
+
+	in := c.Input()
+	assert.Equal(t, 1, in.QueryInt("page", 1))
+	assert.Equal(t, 2.5, in.QueryFloat("ratio", 2.5))
+	assert.True(t, in.QueryBool("draft", true))
+	assert.Equal(t, "all", in.QueryDefault("q", "all"))
+	assert.Equal(t, 10, in.HeaderInt("X-Missing", 10))
+
+	assert.NoError(t, in.Err())
+}
+
+func TestInput_AggregatesErrors(t *testing.T) {
+	c, rec := newInputContext("/?page=abc&draft=maybe&since=yesterday",
+		Params{{Key: "id", Value: "x1"}, {Key: "ref", Value: "not-a-uuid"}})
+
+	in := c.Input()
+	assert.Equal(t, 0, in.ParamInt("id"))
+	assert.Equal(t, "", in.ParamUUID("ref"))
+	assert.Equal(t, 0, in.ParamInt("missing"))
+	assert.Equal(t, 1, in.QueryInt("page", 1), "invalid values fall back to the default")
+	assert.False(t, in.QueryBool("draft", false))
+	assert.True(t, in.QueryTime("since", time.RFC3339, time.Time{}).IsZero())
+
+	err := in.Err()
+	var errs InputErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 6)
+	assert.Equal(t, `param "id": must be an integer, got "x1"`, errs[0].Error())
+	assert.Equal(t, `param "missing": is required`, errs[2].Error())
+	assert.ErrorIs(t, err, errUUID)
+	assert.Contains(t, err.Error(), `query "page": must be an integer, got "abc"; query "draft"`)
+
+	resp := in.Must()
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.False(t, resp.Success)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var body struct {
+		Message string           `json:"message"`
+		Details []map[string]any `json:"details"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Invalid request parameters", body.Message)
+	assert.Len(t, body.Details, 6)
+	assert.Equal(t, map[string]any{"source": "query", "name": "page", "value": "abc", "error": "must be an integer"}, body.Details[3])
+}
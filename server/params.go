@@ -0,0 +1,32 @@
+package server
+
+// Param is a single path parameter captured from the request path.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params holds the path parameters of a request in the order they appear in
+// the path, host params last. Routes have few params, so scanning a slice is
+// cheaper than hashing into a map, and its storage can be reused between
+// requests.
+type Params []Param
+
+// Get returns the value of the param named name, and whether it exists.
+func (ps Params) Get(name string) (string, bool) {
+	for _, p := range ps {
+		if p.Key == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// Map returns the params as a map, e.g. to encode them as a JSON object.
+func (ps Params) Map() map[string]string {
+	m := make(map[string]string, len(ps))
+	for _, p := range ps {
+		m[p.Key] = p.Value
+	}
+	return m
+}
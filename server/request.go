@@ -9,6 +9,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 )
 
@@ -104,10 +105,38 @@ func (c *Context) ShouldBindMultipart(dest any) error {
 // Param returns the value of a path parameter by name.
 // Example: /users/:id -> c.Param("id") returns "123"
 func (c *Context) Param(name string) string {
-	if c.Params == nil {
+	v, _ := c.Params.Get(name)
+	return v
+}
+
+// RoutePattern returns the pattern of the matched route, or the request path
+// if no route matched. Unlike the path, the pattern takes a bounded number of
+// values, which makes it the right label for logs and metrics.
+// Example: /users/:id -> c.RoutePattern() returns "/users/:id" for "/users/42"
+func (c *Context) RoutePattern() string {
+	if c.Route != nil {
+		return c.Route.Path
+	}
+	if c.Request == nil {
 		return ""
 	}
-	return c.Params[name]
+	return c.Request.URL.Path
+}
+
+// Meta returns the metadata value stored under key by the matched route.
+// Example: app.GET("/reports", h, onestrike.WithMeta("scope", "reports:read"))
+// -> c.Meta("scope") returns "reports:read", true
+func (c *Context) Meta(key string) (any, bool) {
+	if c.Route == nil {
+		return nil, false
+	}
+	v, ok := c.Route.Meta[key]
+	return v, ok
+}
+
+// HasTag reports whether the matched route was registered with tag.
+func (c *Context) HasTag(tag string) bool {
+	return c.Route != nil && slices.Contains(c.Route.Tags, tag)
 }
 
 // Query returns the first value of a URL query parameter by key.
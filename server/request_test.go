@@ -21,7 +21,6 @@ func newTestContextWithBody(method, contentType, body string) *Context {
 	return &Context{
 		Request: req,
 		Writer:  w,
-		Params:  make(map[string]string),
 	}
 }
 
@@ -330,7 +329,7 @@ func TestShouldBindMultipart_ParseError(t *testing.T) {
 }
 
 func TestParam_ReturnsValueOrEmpty(t *testing.T) {
-	c := &Context{Params: map[string]string{"id": "123"}}
+	c := &Context{Params: Params{{Key: "id", Value: "123"}}}
 	assert.Equal(t, "123", c.Param("id"))
 	assert.Equal(t, "", c.Param("missing"))
 
@@ -339,6 +338,38 @@ func TestParam_ReturnsValueOrEmpty(t *testing.T) {
 	assert.Equal(t, "", c.Param("id"))
 }
 
+func TestRoutePattern(t *testing.T) {
+	c := &Context{Request: httptest.NewRequest(http.MethodGet, "/users/42", nil)}
+	assert.Equal(t, "/users/42", c.RoutePattern())
+
+	c.Route = &Route{Method: http.MethodGet, Path: "/users/:id"}
+	assert.Equal(t, "/users/:id", c.RoutePattern())
+}
+
+func TestMetaAndHasTag(t *testing.T) {
+	c := &Context{}
+	_, ok := c.Meta("scope")
+	assert.False(t, ok)
+	assert.False(t, c.HasTag("admin"))
+
+	c.Route = &Route{Meta: map[string]any{"scope": "reports:read"}, Tags: []string{"admin"}}
+	v, ok := c.Meta("scope")
+	assert.True(t, ok)
+	assert.Equal(t, "reports:read", v)
+	assert.True(t, c.HasTag("admin"))
+	assert.False(t, c.HasTag("public"))
+}
+
+func TestBindJSON_BodyTooLarge_Returns413(t *testing.T) {
+	c := newTestContextWithBody(http.MethodPost, "application/json", `{"name":"a long name"}`)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 4)
+
+	var dest struct{ Name string }
+	err := c.BindJSON(&dest)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, c.Writer.(*httptest.ResponseRecorder).Code)
+}
+
 func TestQuery_ReturnsFirstValueOrEmpty(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/search?q=golang&lang=go", nil)
 	c := &Context{Request: req}
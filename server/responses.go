@@ -29,7 +29,8 @@ func (c *Context) Blob(code int, data []byte, contentType string) *Response {
 }
 
 // JSON writes the given Response object as JSON with the provided status code.
-// This method respects c.Handled, so it won't write twice if something else already wrote.
+// This method respects c.Handled, so it won't write twice if something else already wrote,
+// and writes nothing once the client is gone.
 func (c *Context) JSON(success bool, message string, details any, code int) *Response {
 	resp := &Response{
 		Success: success,
@@ -37,18 +38,19 @@ func (c *Context) JSON(success bool, message string, details any, code int) *Res
 		Details: details,
 		Code:    code,
 	}
-	if c.Handled {
+	if c.skipWrite() {
 		return resp
 	}
 	c.Writer.Header().Set("Content-Type", "application/json")
-	c.Writer.WriteHeader(code)
+	c.writeHeader(code)
 	_ = json.NewEncoder(c.Writer).Encode(resp)
 	c.Handled = true
 	return resp
 }
 
 // JSON writes the given Response object as JSON with the provided status code.
-// This method respects c.Handled, so it won't write twice if something else already wrote.
+// This method respects c.Handled, so it won't write twice if something else already wrote,
+// and writes nothing once the client is gone.
 func (c *Context) ErrorJSON(message string, details any, code int) *Response {
 	resp := &Response{
 		Success: false,
@@ -56,11 +58,11 @@ func (c *Context) ErrorJSON(message string, details any, code int) *Response {
 		Details: details,
 		Code:    code,
 	}
-	if c.Handled {
+	if c.skipWrite() {
 		return resp
 	}
 	c.Writer.Header().Set("Content-Type", "application/json")
-	c.Writer.WriteHeader(code)
+	c.writeHeader(code)
 	_ = json.NewEncoder(c.Writer).Encode(resp)
 	c.Handled = true
 	return resp
@@ -68,11 +70,11 @@ func (c *Context) ErrorJSON(message string, details any, code int) *Response {
 
 // Redirect sends an HTTP redirect to the specified location.
 func (c *Context) Redirect(code int, location string) *Response {
-	if c.Handled {
+	if c.skipWrite() {
 		return &Response{Success: false, Message: "Response already handled", Code: code}
 	}
 	c.Writer.Header().Set("Location", location)
-	c.Writer.WriteHeader(code)
+	c.writeHeader(code)
 	c.Handled = true
 	return &Response{
 		Success: true,
@@ -84,13 +86,13 @@ func (c *Context) Redirect(code int, location string) *Response {
 // File serves a file from disk with proper Content-Type.
 // If file doesn't exist or can't be read, returns a 404/500 JSON response.
 func (c *Context) File(filePath string) *Response {
-	if c.Handled {
+	if c.skipWrite() {
 		return &Response{Success: false, Message: "Response already handled", Code: 500}
 	}
 
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return &Response{Success: false, Message: "File not found", Code: 404}
+		return c.ErrorJSON("File not found", nil, 404)
 	}
 
 	contentType := http.DetectContentType(data)
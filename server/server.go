@@ -1,63 +1,295 @@
 package server
 
 import (
-	"strings"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
 )
 
+// ErrRouteConflict is returned when a route cannot be registered because it
+// duplicates or is ambiguous with an already registered route.
+var ErrRouteConflict = errors.New("route conflict")
+
+// ErrRouteNotFound is returned when removing or replacing a route that is not
+// registered.
+var ErrRouteNotFound = errors.New("route not found")
+
 // NewRouter creates and returns a new Router instance.
 func NewRouter() *Router {
-	return &Router{routes: make([]route, 0)}
+	r := &Router{}
+	r.table.Store(&routeTable{
+		trees:  make(map[string]*node),
+		routes: make([]*route, 0),
+		names:  make(map[string]*route),
+	})
+	return r
 }
 
 // Handle registers a new route with a specific HTTP method, path, and handler.
+// Params may carry an inline constraint: a named type such as ":id<int>",
+// ":ref<uuid>", ":code<alpha>", ":slug<alnum>" or ":n<uint>", or a regular
+// expression such as ":slug<[a-z-]+>" that must match the whole segment.
+// Requests whose segment fails the constraint fall through to other routes.
+// A trailing "*name" segment captures the rest of the path, slashes included;
+// a bare "*" stores it under the "*" param. It panics if a catch-all is not the
+// last segment of the path or if a constraint is malformed.
+// Each HTTP method gets its own radix tree. Handle panics if the route
+// conflicts with one already registered; use Add to get the error instead.
 func (r *Router) Handle(method, path string, handler HandlerFunc) {
-	r.routes = append(r.routes, route{
-		Method:  method,
-		Path:    path,
+	if err := r.Add(Route{Method: method, Path: path}, handler); err != nil {
+		panic(err)
+	}
+}
+
+// Add registers a route described by rt. It behaves like Handle, and
+// additionally records rt.Name, if set, so URL can build paths for it.
+// A name may be shared by several methods of the same path.
+//
+// It returns an error wrapping ErrRouteConflict, and registers nothing, if
+// the route duplicates an existing method and pattern, if one of its params
+// is ambiguous with another param at the same position (e.g. "/users/:id"
+// and "/users/:name"), or if its name is already used for a different path.
+//
+// Add is safe to call while the router is serving requests: lookups keep
+// using the previous routes until the new ones are complete.
+func (r *Router) Add(rt Route, handler HandlerFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev := r.table.Load()
+	entry := newRoute(rt, handler)
+
+	names := prev.names
+	if rt.Name != "" {
+		if other, ok := names[rt.Name]; ok && other.Path != rt.Path {
+			return fmt.Errorf("%w: route name %q already used for %s", ErrRouteConflict, rt.Name, other.Path)
+		}
+		names = maps.Clone(names)
+		names[rt.Name] = entry
+	}
+
+	// Insert into a copy of the tree; only the nodes along the new route's
+	// path are copied, the rest is shared with the current tree
+	root := &node{}
+	if prevRoot := prev.trees[rt.Method]; prevRoot != nil {
+		root = prevRoot.clone()
+	}
+	if err := root.add(entry); err != nil {
+		return err
+	}
+
+	trees := maps.Clone(prev.trees)
+	trees[rt.Method] = root
+	routes := append(slices.Clip(prev.routes), entry)
+	r.table.Store(&routeTable{trees: trees, routes: routes, names: names})
+	return nil
+}
+
+// Remove unregisters the route for method and pattern path, e.g.
+// ("GET", "/users/:id"). It returns an error wrapping ErrRouteNotFound if
+// there is no such route. Like Add, it is safe to call while serving.
+func (r *Router) Remove(method, path string) error {
+	return r.update(method, func(routes []*route) ([]*route, error) {
+		i, err := indexRoute(routes, method, path)
+		if err != nil {
+			return nil, err
+		}
+		return slices.Delete(routes, i, i+1), nil
+	})
+}
+
+// Replace swaps the route registered for rt.Method and rt.Path for rt and
+// handler, in one step, so no request sees the route missing. It returns an
+// error wrapping ErrRouteNotFound if there is no such route, or wrapping
+// ErrRouteConflict if rt.Name is already used for a different path.
+// Like Add, it is safe to call while serving.
+func (r *Router) Replace(rt Route, handler HandlerFunc) error {
+	entry := newRoute(rt, handler)
+	return r.update(rt.Method, func(routes []*route) ([]*route, error) {
+		i, err := indexRoute(routes, rt.Method, rt.Path)
+		if err != nil {
+			return nil, err
+		}
+		routes[i] = entry
+		return routes, nil
+	})
+}
+
+// newRoute creates the router's entry for rt.
+func newRoute(rt Route, handler HandlerFunc) *route {
+	return &route{
+		Route:   rt,
 		Handler: handler,
+		checks:  paramChecks(rt.Path),
+	}
+}
+
+// indexRoute returns the index of the route for method and path in routes.
+func indexRoute(routes []*route, method, path string) (int, error) {
+	i := slices.IndexFunc(routes, func(rt *route) bool {
+		return rt.Method == method && rt.Path == path
 	})
+	if i < 0 {
+		return -1, fmt.Errorf("%w: %s %s", ErrRouteNotFound, method, path)
+	}
+	return i, nil
+}
+
+// update applies change to a copy of the route list and, if the result is
+// valid, atomically swaps in a new table built from it. Only the tree of
+// method is rebuilt; the others are shared with the previous table, which is
+// never modified, so concurrent lookups need no locking.
+func (r *Router) update(method string, change func([]*route) ([]*route, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev := r.table.Load()
+	routes, err := change(slices.Clone(prev.routes))
+	if err != nil {
+		return err
+	}
+
+	names, err := indexNames(routes)
+	if err != nil {
+		return err
+	}
+	tree, err := buildTree(routes, method)
+	if err != nil {
+		return err
+	}
+
+	trees := maps.Clone(prev.trees)
+	if tree != nil {
+		trees[method] = tree
+	} else {
+		delete(trees, method)
+	}
+	r.table.Store(&routeTable{trees: trees, routes: routes, names: names})
+	return nil
+}
+
+// buildTree builds the routing tree of method from routes, or returns nil if
+// none of them uses method.
+func buildTree(routes []*route, method string) (*node, error) {
+	var root *node
+	for _, rt := range routes {
+		if rt.Method != method {
+			continue
+		}
+		if root == nil {
+			root = &node{}
+		}
+		if err := root.add(rt); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// indexNames maps the names of routes to the routes, and reports a name used
+// for two different paths as a conflict.
+func indexNames(routes []*route) (map[string]*route, error) {
+	names := make(map[string]*route)
+	for _, rt := range routes {
+		if rt.Name == "" {
+			continue
+		}
+		if prev, ok := names[rt.Name]; ok && prev.Path != rt.Path {
+			return nil, fmt.Errorf("%w: route name %q already used for %s", ErrRouteConflict, rt.Name, prev.Path)
+		}
+		names[rt.Name] = rt
+	}
+	return names, nil
 }
 
 // FindHandler attempts to match an incoming request (method + path)
 // against the registered routes. It supports simple path parameters
-// like "/users/:id" and extracts them into a map.
+// like "/users/:id" and trailing catch-alls like "/files/*filepath",
+// and extracts them into a map.
+// Static segments always take priority over parameters, and parameters over
+// catch-alls, regardless of registration order.
 // Returns the matching HandlerFunc and a map of extracted params.
 // If no match is found, it returns (nil, nil).
 func (r *Router) FindHandler(method, path string) (HandlerFunc, map[string]string) {
-	for _, rt := range r.routes {
-		// Skip if method doesn't match
-		if rt.Method != method {
-			continue
-		}
+	_, handler, params := r.Lookup(method, path, nil)
+	if handler == nil {
+		return nil, nil
+	}
+	return handler, params.Map()
+}
 
-		// Split both route and incoming path into parts
-		params := make(map[string]string)
-		rtParts := strings.Split(rt.Path, "/")
-		pParts := strings.Split(path, "/")
+// Lookup works like FindHandler but also returns the matched Route, which is
+// shared with the router and must not be modified, and returns the params as
+// Params. They are appended to ps[:0], so a caller can reuse their storage
+// across requests; pass nil otherwise.
+// If no match is found, it returns nil for the route and handler, and ps[:0].
+func (r *Router) Lookup(method, path string, ps Params) (*Route, HandlerFunc, Params) {
+	ps = ps[:0]
+	root := r.table.Load().trees[method]
+	if root == nil {
+		return nil, nil, ps
+	}
 
-		// Length mismatch -> no match
-		if len(rtParts) != len(pParts) {
-			continue
-		}
+	leaf, out := root.match(path, ps)
+	if leaf == nil {
+		// No matching route found
+		return nil, nil, ps
+	}
+	return &leaf.route.Route, leaf.route.Handler, out
+}
 
-		// Check each segment
-		match := true
-		for i := range rtParts {
-			if strings.HasPrefix(rtParts[i], ":") {
-				// It's a path parameter, capture it
-				params[rtParts[i][1:]] = pParts[i]
-			} else if rtParts[i] != pParts[i] {
-				// Static segment mismatch -> route doesn't match
-				match = false
-				break
-			}
-		}
+// HasRoute reports whether a route is registered for method that matches path.
+func (r *Router) HasRoute(method, path string) bool {
+	root := r.table.Load().trees[method]
+	if root == nil {
+		return false
+	}
+	leaf, _ := root.match(path, nil)
+	return leaf != nil
+}
+
+// FindCaseInsensitivePath looks up path for method ignoring ASCII letter
+// case in static segments. If a route matches, it returns the path rewritten
+// with the casing the route was registered with (param values are kept as
+// given), so callers can redirect clients to the canonical URL.
+func (r *Router) FindCaseInsensitivePath(method, path string) (string, bool) {
+	root := r.table.Load().trees[method]
+	if root == nil {
+		return "", false
+	}
+	fixed, ok := root.matchFold(path, make([]byte, 0, len(path)))
+	if !ok {
+		return "", false
+	}
+	return string(fixed), true
+}
 
-		if match {
-			return rt.Handler, params
+// AllowedMethods returns the sorted list of HTTP methods that have a route
+// matching path. It is used to answer 405 Method Not Allowed with a proper
+// Allow header. It returns nil if no method matches the path.
+func (r *Router) AllowedMethods(path string) []string {
+	var allowed []string
+	for method, root := range r.table.Load().trees {
+		if leaf, _ := root.match(path, nil); leaf != nil {
+			allowed = append(allowed, method)
 		}
 	}
+	sort.Strings(allowed)
+	return allowed
+}
 
-	// No matching route found
-	return nil, nil
+// Routes returns a description of every registered route, in registration
+// order. The returned slice is a copy and may be freely modified.
+func (r *Router) Routes() []Route {
+	table := r.table.Load()
+	routes := make([]Route, len(table.routes))
+	for i, rt := range table.routes {
+		routes[i] = rt.Route
+		routes[i].Middlewares = slices.Clone(rt.Middlewares)
+		routes[i].Meta = maps.Clone(rt.Meta)
+		routes[i].Tags = slices.Clone(rt.Tags)
+	}
+	return routes
 }
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -81,3 +83,408 @@ func TestRouter(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter_StaticBeatsParam(t *testing.T) {
+	named := func(name string) HandlerFunc {
+		return func(c *Context) *Response {
+			return &Response{Success: true, Message: name, Code: 200}
+		}
+	}
+
+	// Register the param route first; the static route must still win.
+	router := NewRouter()
+	router.Handle("GET", "/users/:id", named("param"))
+	router.Handle("GET", "/users/new", named("static"))
+	router.Handle("GET", "/users/:id/edit", named("edit"))
+
+	tests := []struct {
+		path       string
+		wantName   string
+		wantParams map[string]string
+	}{
+		{"/users/new", "static", map[string]string{}},
+		{"/users/42", "param", map[string]string{"id": "42"}},
+		{"/users/newer", "param", map[string]string{"id": "newer"}},
+		// "/users/new" is static but has no "/edit" child, so the router
+		// must backtrack into the param branch.
+		{"/users/new/edit", "edit", map[string]string{"id": "new"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			h, params := router.FindHandler("GET", tt.path)
+			if assert.NotNil(t, h) {
+				assert.Equal(t, tt.wantName, h(nil).Message)
+			}
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func TestRouter_SharedPrefixes(t *testing.T) {
+	router := NewRouter()
+	for _, p := range []string{"/search", "/support", "/s", "/sup", "/team/:team/members/:member"} {
+		path := p
+		router.Handle("GET", path, func(c *Context) *Response {
+			return &Response{Success: true, Message: path, Code: 200}
+		})
+	}
+
+	for _, p := range []string{"/search", "/support", "/s", "/sup"} {
+		h, _ := router.FindHandler("GET", p)
+		if assert.NotNil(t, h, p) {
+			assert.Equal(t, p, h(nil).Message)
+		}
+	}
+
+	h, params := router.FindHandler("GET", "/team/core/members/ada")
+	assert.NotNil(t, h)
+	assert.Equal(t, map[string]string{"team": "core", "member": "ada"}, params)
+
+	h, _ = router.FindHandler("GET", "/su")
+	assert.Nil(t, h)
+	h, _ = router.FindHandler("GET", "/team//members/ada")
+	assert.Nil(t, h, "params must not match empty segments")
+}
+
+func TestRouter_StaticMatchDoesNotAllocate(t *testing.T) {
+	router := NewRouter()
+	testHandler := func(c *Context) *Response { return nil }
+	router.Handle("GET", "/api/v1/users", testHandler)
+	router.Handle("GET", "/api/v1/users/:id", testHandler)
+	router.Handle("GET", "/api/v1/teams", testHandler)
+
+	root := router.table.Load().trees["GET"]
+	allocs := testing.AllocsPerRun(100, func() {
+		if leaf, _ := root.match("/api/v1/teams", nil); leaf == nil {
+			t.Fatal("expected a match")
+		}
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestRouter_CatchAll(t *testing.T) {
+	named := func(name string) HandlerFunc {
+		return func(c *Context) *Response {
+			return &Response{Success: true, Message: name, Code: 200}
+		}
+	}
+
+	router := NewRouter()
+	router.Handle("GET", "/files/*filepath", named("files"))
+	router.Handle("GET", "/files/readme", named("readme"))
+	router.Handle("GET", "/files/:name/info", named("info"))
+	router.Handle("GET", "/assets/*", named("assets"))
+
+	tests := []struct {
+		path       string
+		wantName   string
+		wantParams map[string]string
+	}{
+		{"/files/readme", "readme", map[string]string{}},
+		{"/files/report.pdf/info", "info", map[string]string{"name": "report.pdf"}},
+		{"/files/report.pdf", "files", map[string]string{"filepath": "report.pdf"}},
+		{"/files/a/b/c.txt", "files", map[string]string{"filepath": "a/b/c.txt"}},
+		{"/files/readme/extra", "files", map[string]string{"filepath": "readme/extra"}},
+		{"/files/", "files", map[string]string{"filepath": ""}},
+		{"/assets/css/site.css", "assets", map[string]string{"*": "css/site.css"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			h, params := router.FindHandler("GET", tt.path)
+			if assert.NotNil(t, h) {
+				assert.Equal(t, tt.wantName, h(nil).Message)
+			}
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+
+	h, _ := router.FindHandler("GET", "/files")
+	assert.Nil(t, h, "catch-all requires the preceding slash")
+}
+
+func TestRouter_CatchAllMustBeLast(t *testing.T) {
+	router := NewRouter()
+	assert.Panics(t, func() {
+		router.Handle("GET", "/files/*filepath/meta", func(c *Context) *Response { return nil })
+	})
+}
+
+func TestRouter_ParamConstraints(t *testing.T) {
+	named := func(name string) HandlerFunc {
+		return func(c *Context) *Response {
+			return &Response{Success: true, Message: name, Code: 200}
+		}
+	}
+
+	router := NewRouter()
+	router.Handle("GET", "/users/:name", named("name"))
+	router.Handle("GET", "/users/:id<int>", named("id"))
+	router.Handle("GET", "/orders/:ref<uuid>", named("order"))
+	router.Handle("GET", "/tags/:slug<[a-z-]+>", named("tag"))
+
+	tests := []struct {
+		path       string
+		wantName   string
+		wantParams map[string]string
+	}{
+		{"/users/42", "id", map[string]string{"id": "42"}},
+		{"/users/-7", "id", map[string]string{"id": "-7"}},
+		{"/users/ada", "name", map[string]string{"name": "ada"}},
+		{"/orders/3f2b8c1e-9d4a-4f6b-8a7e-1c2d3e4f5a6b", "order", map[string]string{"ref": "3f2b8c1e-9d4a-4f6b-8a7e-1c2d3e4f5a6b"}},
+		{"/tags/go-lang", "tag", map[string]string{"slug": "go-lang"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			h, params := router.FindHandler("GET", tt.path)
+			if assert.NotNil(t, h) {
+				assert.Equal(t, tt.wantName, h(nil).Message)
+			}
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+
+	for _, path := range []string{"/orders/42", "/orders/3f2b8c1e9d4a4f6b8a7e1c2d3e4f5a6b", "/tags/Go", "/tags/go_lang"} {
+		h, _ := router.FindHandler("GET", path)
+		assert.Nil(t, h, path)
+	}
+}
+
+func TestRouter_InvalidConstraintPanics(t *testing.T) {
+	noop := func(c *Context) *Response { return nil }
+	for _, pattern := range []string{"/users/:id<int", "/users/:id<>", "/users/:id<[a-z>"} {
+		assert.Panics(t, func() { NewRouter().Handle("GET", pattern, noop) }, pattern)
+	}
+}
+
+func TestRouter_AllowedMethods(t *testing.T) {
+	router := NewRouter()
+	noop := func(c *Context) *Response { return nil }
+	router.Handle("POST", "/users", noop)
+	router.Handle("GET", "/users", noop)
+	router.Handle("DELETE", "/users/:id", noop)
+
+	assert.Equal(t, []string{"GET", "POST"}, router.AllowedMethods("/users"))
+	assert.Equal(t, []string{"DELETE"}, router.AllowedMethods("/users/1"))
+	assert.Nil(t, router.AllowedMethods("/teams"))
+}
+
+func TestRouter_Routes(t *testing.T) {
+	router := NewRouter()
+	noop := func(c *Context) *Response { return nil }
+	router.Handle("GET", "/users", noop)
+	router.Add(Route{Method: "POST", Path: "/api/users", Name: "users.create", Prefix: "/api", Middlewares: []string{"auth"}}, noop)
+
+	assert.Equal(t, []Route{
+		{Method: "GET", Path: "/users"},
+		{Method: "POST", Path: "/api/users", Name: "users.create", Prefix: "/api", Middlewares: []string{"auth"}},
+	}, router.Routes())
+}
+
+func TestRouter_Conflicts(t *testing.T) {
+	noop := func(c *Context) *Response { return nil }
+
+	tests := []struct {
+		name     string
+		existing string
+		pattern  string
+		wantErr  bool
+	}{
+		{"exact duplicate", "/users", "/users", true},
+		{"duplicate param route", "/users/:id", "/users/:id", true},
+		{"param names differ", "/users/:id", "/users/:name", true},
+		{"param names differ deeper", "/users/:id/posts", "/users/:name/comments", true},
+		{"same constraint, names differ", "/users/:id<int>", "/users/:num<int>", true},
+		{"catch-all names differ", "/files/*path", "/files/*name", true},
+		{"static vs param", "/users/:id", "/users/new", false},
+		{"constrained vs unconstrained", "/users/:id<int>", "/users/:name", false},
+		{"different constraints", "/users/:id<int>", "/users/:ref<uuid>", false},
+		{"shared param prefix", "/users/:id", "/users/:id/posts", false},
+		{"param vs catch-all", "/files/:name", "/files/*path", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter()
+			assert.NoError(t, router.Add(Route{Method: "GET", Path: tt.existing}, noop))
+
+			err := router.Add(Route{Method: "GET", Path: tt.pattern}, noop)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrRouteConflict)
+				assert.Len(t, router.Routes(), 1)
+				assert.Panics(t, func() { router.Handle("GET", tt.pattern, noop) })
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Other methods never conflict
+			assert.NoError(t, router.Add(Route{Method: "POST", Path: tt.pattern}, noop))
+		})
+	}
+}
+
+func TestRouter_FindCaseInsensitivePath(t *testing.T) {
+	router := NewRouter()
+	noop := func(c *Context) *Response { return nil }
+	router.Handle("GET", "/Users/:id<int>", noop)
+	router.Handle("GET", "/users/profile", noop)
+	router.Handle("GET", "/files/*path", noop)
+
+	tests := []struct {
+		path  string
+		want  string
+		found bool
+	}{
+		{"/USERS/PROFILE", "/users/profile", true},
+		{"/users/42", "/Users/42", true},
+		{"/FILES/A/b", "/files/A/b", true},
+		{"/users/abc", "", false},
+		{"/teams", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := router.FindCaseInsensitivePath("GET", tt.path)
+		assert.Equal(t, tt.found, ok, tt.path)
+		assert.Equal(t, tt.want, got, tt.path)
+	}
+
+	_, ok := router.FindCaseInsensitivePath("POST", "/users/profile")
+	assert.False(t, ok)
+	assert.True(t, router.HasRoute("GET", "/users/profile"))
+	assert.False(t, router.HasRoute("POST", "/users/profile"))
+}
+
+func TestRouter_Lookup(t *testing.T) {
+	router := NewRouter()
+	err := router.Add(Route{Method: "GET", Path: "/users/:id", Name: "users.show", Tags: []string{"public"}}, func(c *Context) *Response {
+		return &Response{Success: true, Code: 200}
+	})
+	assert.NoError(t, err)
+
+	rt, h, params := router.Lookup("GET", "/users/42", nil)
+	assert.NotNil(t, h)
+	assert.Equal(t, "/users/:id", rt.Path)
+	assert.Equal(t, "users.show", rt.Name)
+	assert.Equal(t, []string{"public"}, rt.Tags)
+	assert.Equal(t, Params{{Key: "id", Value: "42"}}, params)
+
+	// The params' storage is reused
+	buf := make(Params, 0, 4)
+	_, _, params = router.Lookup("GET", "/users/7", buf)
+	assert.Equal(t, Params{{Key: "id", Value: "7"}}, params)
+	assert.Same(t, &buf[:1][0], &params[0])
+
+	rt, h, params = router.Lookup("GET", "/posts/42", params)
+	assert.Nil(t, rt)
+	assert.Nil(t, h)
+	assert.Empty(t, params)
+}
+
+func TestRouter_RemoveAndReplace(t *testing.T) {
+	named := func(name string) HandlerFunc {
+		return func(c *Context) *Response {
+			return &Response{Success: true, Message: name, Code: 200}
+		}
+	}
+
+	router := NewRouter()
+	router.Handle("GET", "/users/:id", named("show"))
+	router.Handle("GET", "/users/new", named("new"))
+	assert.NoError(t, router.Add(Route{Method: "POST", Path: "/users", Name: "users.create"}, named("create")))
+
+	// Remove keeps the rest of the tree intact
+	assert.NoError(t, router.Remove("GET", "/users/new"))
+	h, params := router.FindHandler("GET", "/users/new")
+	if assert.NotNil(t, h) {
+		assert.Equal(t, "show", h(nil).Message)
+		assert.Equal(t, "new", params["id"])
+	}
+	assert.ErrorIs(t, router.Remove("GET", "/users/new"), ErrRouteNotFound)
+	assert.Len(t, router.Routes(), 2)
+
+	// Removing the last route of a method drops its name too
+	assert.NoError(t, router.Remove("POST", "/users"))
+	assert.False(t, router.HasRoute("POST", "/users"))
+	_, err := router.URL("users.create")
+	assert.ErrorIs(t, err, ErrUnknownRoute)
+
+	// Replace swaps handler and description in place
+	assert.NoError(t, router.Replace(Route{Method: "GET", Path: "/users/:id", Name: "users.show"}, named("show2")))
+	h, _ = router.FindHandler("GET", "/users/7")
+	assert.Equal(t, "show2", h(nil).Message)
+	u, err := router.URL("users.show", "id", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/7", u)
+	assert.ErrorIs(t, router.Replace(Route{Method: "GET", Path: "/posts/:id"}, named("x")), ErrRouteNotFound)
+
+	// A route can be registered again once removed
+	assert.NoError(t, router.Remove("GET", "/users/:id"))
+	assert.NoError(t, router.Add(Route{Method: "GET", Path: "/users/:name"}, named("by-name")))
+}
+
+func TestRouter_AddDoesNotModifyPreviousTree(t *testing.T) {
+	testHandler := func(c *Context) *Response { return nil }
+	router := NewRouter()
+	router.Handle("GET", "/api/v1/users", testHandler)
+	router.Handle("GET", "/api/v1/users/:id", testHandler)
+	before := router.table.Load()
+
+	router.Handle("GET", "/api/v2/users", testHandler)
+	router.Handle("GET", "/api/v1/users/:id/posts", testHandler)
+	router.Handle("GET", "/api/v1/u", testHandler)
+
+	// The old snapshot still sees exactly the old routes
+	root := before.trees["GET"]
+	for path, want := range map[string]bool{
+		"/api/v1/users":          true,
+		"/api/v1/users/42":       true,
+		"/api/v2/users":          false,
+		"/api/v1/users/42/posts": false,
+		"/api/v1/u":              false,
+	} {
+		leaf, _ := root.match(path, nil)
+		assert.Equal(t, want, leaf != nil, path)
+	}
+	assert.Len(t, before.routes, 2)
+}
+
+func TestRouter_ConcurrentChanges(t *testing.T) {
+	testHandler := func(c *Context) *Response { return &Response{Code: 200} }
+	router := NewRouter()
+	router.Handle("GET", "/stable/:id", testHandler)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				h, _ := router.FindHandler("GET", "/stable/1")
+				if h == nil {
+					t.Error("stable route disappeared")
+					return
+				}
+				router.FindHandler("GET", "/plugins/3/items")
+				router.AllowedMethods("/plugins/3/items")
+				router.Routes()
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		path := fmt.Sprintf("/plugins/%d/items", i%8)
+		if err := router.Add(Route{Method: "GET", Path: path}, testHandler); err == nil {
+			assert.NoError(t, router.Replace(Route{Method: "GET", Path: path}, testHandler))
+			assert.NoError(t, router.Remove("GET", path))
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
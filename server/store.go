@@ -0,0 +1,71 @@
+package server
+
+import "fmt"
+
+// ContextKey is the type of request context keys that Context.Get looks up.
+// net/http middleware can hand values to handlers by storing them in the
+// request's context.Context under a ContextKey:
+//
+//	ctx := context.WithValue(r.Context(), server.ContextKey("user"), user)
+//	next.ServeHTTP(w, r.WithContext(ctx))
+//	// later, in a handler: c.Get("user")
+type ContextKey string
+
+// Set stores a request-scoped value under key, for later middleware and the
+// handler to read with Get. Values live as long as the request and are kept
+// apart from Params.
+// Example: c.Set("user", user)
+func (c *Context) Set(key string, value any) {
+	if c.values == nil {
+		c.values = make(map[string]any)
+	}
+	c.values[key] = value
+}
+
+// Get returns the value stored under key with Set. If there is none, it
+// falls back to the request's context.Context value for ContextKey(key).
+func (c *Context) Get(key string) (any, bool) {
+	if v, ok := c.values[key]; ok {
+		return v, true
+	}
+	if c.Request != nil {
+		if v := c.Request.Context().Value(ContextKey(key)); v != nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// MustGet returns the value stored under key, and panics if there is none.
+// Use it for values a middleware earlier in the chain always sets.
+func (c *Context) MustGet(key string) any {
+	v, ok := c.Get(key)
+	if !ok {
+		panic(fmt.Sprintf("onestrike: no value for key %q in context", key))
+	}
+	return v
+}
+
+// GetAs returns the value stored under key as a T. It reports false if there
+// is none or if it is not a T.
+// Example: user, ok := server.GetAs[*User](c, "user")
+func GetAs[T any](c *Context, key string) (T, bool) {
+	v, ok := c.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	t, ok := v.(T)
+	return t, ok
+}
+
+// MustGetAs returns the value stored under key as a T, and panics if there
+// is none or if it is not a T.
+func MustGetAs[T any](c *Context, key string) T {
+	v := c.MustGet(key)
+	t, ok := v.(T)
+	if !ok {
+		panic(fmt.Sprintf("onestrike: value for key %q is %T, not %T", key, v, t))
+	}
+	return t
+}
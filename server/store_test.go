@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_SetGet(t *testing.T) {
+	c := &Context{Request: httptest.NewRequest("GET", "/", nil)}
+
+	_, ok := c.Get("user")
+	assert.False(t, ok)
+
+	c.Set("user", "alice")
+	c.Set("count", 3)
+
+	v, ok := c.Get("user")
+	assert.True(t, ok)
+	assert.Equal(t, "alice", v)
+	assert.Equal(t, 3, c.MustGet("count"))
+	assert.Empty(t, c.Params, "values must not leak into Params")
+
+	c.Set("user", "bob")
+	assert.Equal(t, "bob", c.MustGet("user"))
+}
+
+func TestContext_GetFallsBackToRequestContext(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ContextKey("user"), "alice"))
+	c := &Context{Request: req}
+
+	v, ok := c.Get("user")
+	assert.True(t, ok)
+	assert.Equal(t, "alice", v)
+
+	// Values set on the Context take precedence
+	c.Set("user", "bob")
+	assert.Equal(t, "bob", c.MustGet("user"))
+
+	// Keys of other types are not looked up
+	type otherKey string
+	req = req.WithContext(context.WithValue(req.Context(), otherKey("role"), "admin"))
+	c = &Context{Request: req}
+	_, ok = c.Get("role")
+	assert.False(t, ok)
+}
+
+func TestContext_MustGetPanics(t *testing.T) {
+	c := &Context{}
+	assert.PanicsWithValue(t, `onestrike: no value for key "user" in context`, func() {
+		c.MustGet("user")
+	})
+}
+
+func TestGetAs(t *testing.T) {
+	c := &Context{}
+	c.Set("count", 3)
+
+	n, ok := GetAs[int](c, "count")
+	assert.True(t, ok)
+	assert.Equal(t, 3, n)
+
+	s, ok := GetAs[string](c, "count")
+	assert.False(t, ok)
+	assert.Empty(t, s)
+
+	_, ok = GetAs[int](c, "missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, 3, MustGetAs[int](c, "count"))
+	assert.Panics(t, func() { MustGetAs[string](c, "count") })
+}
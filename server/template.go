@@ -53,12 +53,12 @@ func (tr *TemplateRenderer) Render(w http.ResponseWriter, name string, data inte
 
 // Render helper on Context so users can do c.Render("index.html", data)
 func (c *Context) Render(renderer *TemplateRenderer, code int, name string, data interface{}) *Response {
-	if c.Handled {
+	if c.skipWrite() {
 		return &Response{Success: false, Message: "Response already handled", Code: code}
 	}
 
 	c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
-	c.Writer.WriteHeader(code)
+	c.writeHeader(code)
 
 	err := renderer.Render(c.Writer, name, data)
 	if err != nil {
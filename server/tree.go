@@ -0,0 +1,332 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// nodeKind identifies how a node in the routing tree consumes the request path.
+type nodeKind uint8
+
+const (
+	staticKind   nodeKind = iota // matches its prefix literally
+	paramKind                    // matches exactly one path segment, e.g. ":id"
+	catchAllKind                 // matches the rest of the path, e.g. "*filepath"
+)
+
+// node is a single node of the compressed radix tree used by Router.
+// Static nodes share common prefixes with their siblings, so a lookup only
+// compares each byte of the request path once. Param nodes always occupy a
+// whole path segment, and a catch-all node is always the last node of a route.
+//
+// Children are tried in a fixed priority order: static children first, then
+// param children, then the catch-all child. If a branch fails further down the
+// tree the lookup backtracks and tries the next candidate, so the result never
+// depends on the order in which routes were registered.
+type node struct {
+	kind     nodeKind
+	prefix   string            // static path fragment, or the raw ":name"/"*name" token
+	name     string            // parameter name (param and catch-all nodes only)
+	check    func(string) bool // optional constraint on a param's value
+	indices  string            // first byte of each static child, aligned with static
+	static   []*node           // static children
+	params   []*node           // param children, constrained ones first
+	catchAll *node             // catch-all child, if any
+	route    *route            // route terminating at this node, if any
+}
+
+// clone returns a shallow copy of n. Trees are copy-on-write: insert clones
+// every existing node it descends into before changing it, so a tree that
+// concurrent lookups may be reading is never modified. n itself must already
+// be a node the caller owns.
+func (n *node) clone() *node {
+	c := *n
+	return &c
+}
+
+// add inserts rt's pattern below n and attaches rt to the node at which it
+// terminates. It reports an ErrRouteConflict error if the pattern is
+// ambiguous with a registered one or already has a route.
+func (n *node) add(rt *route) error {
+	leaf, err := n.insert(rt.Path)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", rt.Method, rt.Path, err)
+	}
+	if leaf.route != nil {
+		return fmt.Errorf("%w: %s %s is already registered as %s", ErrRouteConflict, rt.Method, rt.Path, leaf.route.Path)
+	}
+	leaf.route = rt
+	return nil
+}
+
+// insert adds the pattern to the tree rooted at n and returns the node at which
+// it terminates. The caller is responsible for attaching the route to it.
+// It returns an ErrRouteConflict error if a param or catch-all would be
+// ambiguous with one already registered at the same position, and panics if
+// a catch-all segment is not the last segment of the pattern.
+func (n *node) insert(pattern string) (*node, error) {
+	for {
+		i := indexParam(pattern)
+		if i < 0 {
+			return n.insertStatic(pattern), nil
+		}
+
+		n = n.insertStatic(pattern[:i])
+
+		if pattern[i] == '*' {
+			if strings.IndexByte(pattern[i:], '/') >= 0 {
+				panic("onestrike: catch-all must be the last segment in route pattern")
+			}
+			return n.insertCatchAll(pattern[i:])
+		}
+
+		end := segmentEnd(pattern, i)
+
+		var err error
+		if n, err = n.insertParam(pattern[i:end]); err != nil {
+			return nil, err
+		}
+		pattern = pattern[end:]
+	}
+}
+
+// insertStatic inserts a literal fragment below n, splitting existing nodes
+// where they only share part of their prefix with path.
+func (n *node) insertStatic(path string) *node {
+	for path != "" {
+		idx := strings.IndexByte(n.indices, path[0])
+		if idx < 0 {
+			child := &node{kind: staticKind, prefix: path}
+			n.indices += string(path[0])
+			n.static = append(slices.Clip(n.static), child)
+			return child
+		}
+
+		child := n.static[idx].clone()
+		n.static = slices.Clone(n.static)
+		n.static[idx] = child
+		l := commonPrefix(path, child.prefix)
+		if l < len(child.prefix) {
+			// Split the child: it keeps the shared prefix and the remainder
+			// (with all of its children and route) moves one level down.
+			tail := *child
+			tail.prefix = child.prefix[l:]
+			*child = node{
+				kind:    staticKind,
+				prefix:  child.prefix[:l],
+				indices: string(tail.prefix[0]),
+				static:  []*node{&tail},
+			}
+		}
+
+		path = path[l:]
+		n = child
+	}
+	return n
+}
+
+// insertParam returns the param child of n matching token (e.g. ":id" or
+// ":id<int>"), creating it if no child with the same token exists yet.
+// Constrained params are kept ahead of unconstrained ones so that a request
+// only falls back to a catch-any param once every constraint has failed.
+// Two params with the same constraint (or both without one) but different
+// names would be ambiguous, so that is reported as a conflict.
+func (n *node) insertParam(token string) (*node, error) {
+	for i, child := range n.params {
+		if child.prefix == token {
+			child = child.clone()
+			n.params = slices.Clone(n.params)
+			n.params[i] = child
+			return child, nil
+		}
+	}
+
+	name, check := parseParamToken(token)
+	for _, child := range n.params {
+		if child.prefix[len(child.name)+1:] == token[len(name)+1:] {
+			return nil, fmt.Errorf("%w: param %q is ambiguous with %q at the same position", ErrRouteConflict, token, child.prefix)
+		}
+	}
+
+	child := &node{kind: paramKind, prefix: token, name: name, check: check}
+
+	pos := len(n.params)
+	if check != nil {
+		for i, p := range n.params {
+			if p.check == nil {
+				pos = i
+				break
+			}
+		}
+	}
+	n.params = slices.Insert(slices.Clip(n.params), pos, child)
+	return child, nil
+}
+
+// insertCatchAll returns the catch-all child of n, creating it for token
+// (e.g. "*filepath") if n has none yet. A bare "*" captures into the "*" param.
+// A node has at most one catch-all, so a differently named one is a conflict.
+func (n *node) insertCatchAll(token string) (*node, error) {
+	if n.catchAll == nil {
+		name := token[1:]
+		if name == "" {
+			name = "*"
+		}
+		n.catchAll = &node{kind: catchAllKind, prefix: token, name: name}
+	} else if n.catchAll.prefix != token {
+		return nil, fmt.Errorf("%w: catch-all %q is ambiguous with %q at the same position", ErrRouteConflict, token, n.catchAll.prefix)
+	} else {
+		n.catchAll = n.catchAll.clone()
+	}
+	return n.catchAll, nil
+}
+
+// match walks the tree looking for a route matching path. Captured parameters
+// are appended to ps; nothing is allocated unless a parameter is captured.
+// It returns the terminating node, or nil if no route matches.
+func (n *node) match(path string, ps Params) (*node, Params) {
+	switch n.kind {
+	case staticKind:
+		if !strings.HasPrefix(path, n.prefix) {
+			return nil, nil
+		}
+		path = path[len(n.prefix):]
+	case paramKind:
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end == 0 {
+			// Params never match an empty segment
+			return nil, nil
+		}
+		if n.check != nil && !n.check(path[:end]) {
+			return nil, nil
+		}
+		ps = append(ps, Param{Key: n.name, Value: path[:end]})
+		path = path[end:]
+	case catchAllKind:
+		// Catch-alls swallow the remainder, slashes included (possibly empty)
+		return n, append(ps, Param{Key: n.name, Value: path})
+	}
+
+	if path == "" {
+		if n.route != nil {
+			return n, ps
+		}
+	} else {
+		// Static children take priority over params
+		if idx := strings.IndexByte(n.indices, path[0]); idx >= 0 {
+			if leaf, out := n.static[idx].match(path, ps); leaf != nil {
+				return leaf, out
+			}
+		}
+
+		for _, child := range n.params {
+			if leaf, out := child.match(path, ps); leaf != nil {
+				return leaf, out
+			}
+		}
+	}
+
+	// Catch-all is the route of last resort at this level
+	if n.catchAll != nil {
+		return n.catchAll.match(path, ps)
+	}
+
+	return nil, nil
+}
+
+// matchFold is a case-insensitive variant of match. Static fragments are
+// compared with ASCII case folding and appended to buf in their registered
+// casing, while param and catch-all values are copied from path as-is.
+// It returns the corrected path and whether a route was found.
+func (n *node) matchFold(path string, buf []byte) ([]byte, bool) {
+	switch n.kind {
+	case staticKind:
+		if len(path) < len(n.prefix) || !strings.EqualFold(path[:len(n.prefix)], n.prefix) {
+			return nil, false
+		}
+		buf = append(buf, n.prefix...)
+		path = path[len(n.prefix):]
+	case paramKind:
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end == 0 || (n.check != nil && !n.check(path[:end])) {
+			return nil, false
+		}
+		buf = append(buf, path[:end]...)
+		path = path[end:]
+	case catchAllKind:
+		return append(buf, path...), true
+	}
+
+	if path == "" {
+		if n.route != nil {
+			return buf, true
+		}
+	} else {
+		first := toLowerASCII(path[0])
+		for i := 0; i < len(n.indices); i++ {
+			if toLowerASCII(n.indices[i]) != first {
+				continue
+			}
+			if out, ok := n.static[i].matchFold(path, buf); ok {
+				return out, true
+			}
+		}
+
+		for _, child := range n.params {
+			if out, ok := child.matchFold(path, buf); ok {
+				return out, true
+			}
+		}
+	}
+
+	if n.catchAll != nil {
+		return n.catchAll.matchFold(path, buf)
+	}
+
+	return nil, false
+}
+
+// toLowerASCII lowercases an ASCII letter and returns any other byte as is.
+func toLowerASCII(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// indexParam returns the index of the first ':' or '*' that starts a path
+// segment, or -1 if the pattern contains no parameters.
+func indexParam(pattern string) int {
+	for i := 0; i < len(pattern); i++ {
+		if (pattern[i] == ':' || pattern[i] == '*') && (i == 0 || pattern[i-1] == '/') {
+			return i
+		}
+	}
+	return -1
+}
+
+// segmentEnd returns the index of the '/' ending the segment that starts at
+// i, or len(pattern) if it is the last segment.
+func segmentEnd(pattern string, i int) int {
+	if end := strings.IndexByte(pattern[i:], '/'); end >= 0 {
+		return i + end
+	}
+	return len(pattern)
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b.
+func commonPrefix(a, b string) int {
+	n := min(len(a), len(b))
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -2,20 +2,52 @@ package server
 
 import (
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// Route describes a registered route: its HTTP method, path pattern,
+// an optional name used to build URLs back to it, the prefix and host of the
+// group it was registered through, the names of the middleware wrapping it
+// and the options it was registered with.
+type Route struct {
+	Method      string         `json:"method"`               // HTTP method (GET, POST, PUT, etc.)
+	Path        string         `json:"path"`                 // Route pattern, e.g. "/users/:id"
+	Name        string         `json:"name,omitempty"`       // Optional unique name, e.g. "users.show"
+	Prefix      string         `json:"prefix,omitempty"`     // Group prefix, e.g. "/api/v1"
+	Host        string         `json:"host,omitempty"`       // Host pattern, e.g. ":tenant.example.com"
+	Middlewares []string       `json:"middlewares"`          // Middleware chain, outermost first
+	Meta        map[string]any `json:"meta,omitempty"`       // Arbitrary metadata, read by middleware at request time
+	Tags        []string       `json:"tags,omitempty"`       // Free-form tags, e.g. "public" or "admin"
+	BodyLimit   int64          `json:"body_limit,omitempty"` // Maximum request body size in bytes, 0 for none
+	Timeout     time.Duration  `json:"timeout,omitempty"`    // Deadline for the handler, 0 for none
+}
+
 // route represents a single registered route in the router.
-// It contains the HTTP method, the route path pattern, and the handler function.
+// It contains the route description and the handler function.
 type route struct {
-	Method  string      // HTTP method (GET, POST, PUT, etc.)
-	Path    string      // Route pattern, e.g. "/users/:id"
-	Handler HandlerFunc // Function to handle requests matching this route
+	Route
+	Handler HandlerFunc                  // Function to handle requests matching this route
+	checks  map[string]func(string) bool // Param constraints, used when building URLs
 }
 
 // Router is a minimal HTTP router that supports method-based routing
 // and simple path parameters (e.g., /users/:id).
+// Routes are stored in one compressed radix tree per HTTP method.
+// Lookups read an immutable snapshot of the routes, and changes swap in a
+// new snapshot atomically, so routes can be added, removed and replaced
+// while requests are being served.
 type Router struct {
-	routes []route // List of all registered routes
+	mu    sync.Mutex                 // Serializes changes
+	table atomic.Pointer[routeTable] // Current routes
+}
+
+// routeTable is an immutable snapshot of a Router's routes.
+type routeTable struct {
+	trees  map[string]*node  // Root node of the routing tree for each method
+	routes []*route          // List of all registered routes, in registration order
+	names  map[string]*route // Named routes, for reverse URL generation
 }
 
 // Response is the unified return type for all handlers in OneStrike.
@@ -37,14 +69,31 @@ type Response struct {
 // Fields:
 //   - Writer: the http.ResponseWriter to write responses.
 //   - Request: the incoming HTTP request.
-//   - Params: a map of path parameters extracted from the route (e.g., ":id").
+//   - Params: the path parameters extracted from the route (e.g., ":id").
+//   - Router: the router that matched the request, used to build URLs.
+//   - Route: the route that matched the request, or nil if none did. It carries
+//     the pattern, name, group prefix and host, and the route's options; its
+//     Middlewares are not filled in (see Server.Routes). It is shared between
+//     requests and must not be modified.
+//
+// Request-scoped values are kept apart from Params, see Set and Get.
+//
+// The server reuses Contexts across requests: a Context, its Params and its
+// values must not be used once the handler has returned, including as a
+// context.Context. Copy the values needed later, and give work that outlives
+// the request context.WithoutCancel(c.Request.Context()).
 
 type Context struct {
 	Writer    http.ResponseWriter
 	Request   *http.Request
-	Params    map[string]string
+	Params    Params
 	Handled   bool
 	Templates *TemplateRenderer
+	Router    *Router
+	Route     *Route
+
+	values map[string]any // request-scoped values, see Set
+	status int            // status code written by the helpers, see Status
 }
 
 // HandlerFunc defines the signature for all route handlers in OneStrike.
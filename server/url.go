@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnknownRoute     = errors.New("unknown route name")
+	ErrInvalidURLParams = errors.New("invalid URL params")
+)
+
+// URL builds the path of the route registered under name, substituting
+// its parameters from params, which are given as key/value pairs:
+//
+//	router.URL("users.show", "id", 42) // "/users/42"
+//
+// Values are path-escaped; catch-all values keep their slashes. It returns
+// an error if the route is unknown, a parameter is missing, unknown or
+// fails the route's constraint.
+func (r *Router) URL(name string, params ...any) (string, error) {
+	rt, ok := r.table.Load().names[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownRoute, name)
+	}
+	return rt.buildURL(params)
+}
+
+// FuncMap returns template functions bound to this router, to be passed to
+// NewTemplateRenderer. It provides:
+//
+//	{{ url "users.show" "id" .ID }}
+func (r *Router) FuncMap() template.FuncMap {
+	return template.FuncMap{"url": r.URL}
+}
+
+// URL builds the path of a named route, see Router.URL.
+// Example: c.Redirect(302, must(c.URL("users.show", "id", 42)))
+func (c *Context) URL(name string, params ...any) (string, error) {
+	if c.Router == nil {
+		return "", fmt.Errorf("%w: %q (no router on context)", ErrUnknownRoute, name)
+	}
+	return c.Router.URL(name, params...)
+}
+
+// buildURL substitutes key/value params into the route's pattern.
+func (rt *route) buildURL(pairs []any) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("%w: odd number of key/value arguments", ErrInvalidURLParams)
+	}
+
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return "", fmt.Errorf("%w: param key %v is not a string", ErrInvalidURLParams, pairs[i])
+		}
+		values[key] = fmt.Sprint(pairs[i+1])
+	}
+
+	var b strings.Builder
+	pattern := rt.Path
+	for {
+		i := indexParam(pattern)
+		if i < 0 {
+			b.WriteString(pattern)
+			break
+		}
+		b.WriteString(pattern[:i])
+
+		end := segmentEnd(pattern, i)
+
+		name := paramName(pattern[i:end])
+		value, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("%w: missing param %q for route %q", ErrInvalidURLParams, name, rt.Name)
+		}
+		delete(values, name)
+
+		if pattern[i] == '*' {
+			parts := strings.Split(value, "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			b.WriteString(strings.Join(parts, "/"))
+		} else {
+			if value == "" {
+				return "", fmt.Errorf("%w: empty param %q for route %q", ErrInvalidURLParams, name, rt.Name)
+			}
+			if check := rt.checks[name]; check != nil && !check(value) {
+				return "", fmt.Errorf("%w: param %q=%q violates constraint of route %q", ErrInvalidURLParams, name, value, rt.Name)
+			}
+			b.WriteString(url.PathEscape(value))
+		}
+		pattern = pattern[end:]
+	}
+
+	// Anything left over does not exist in the pattern
+	if len(values) > 0 {
+		unknown := make([]string, 0, len(values))
+		for key := range values {
+			unknown = append(unknown, key)
+		}
+		sort.Strings(unknown)
+		return "", fmt.Errorf("%w: unknown params %v for route %q", ErrInvalidURLParams, unknown, rt.Name)
+	}
+
+	return b.String(), nil
+}
+
+// paramName returns the name a ":name<constraint>" or "*name" token
+// captures into.
+func paramName(token string) string {
+	if token[0] == '*' {
+		if token == "*" {
+			return "*"
+		}
+		return token[1:]
+	}
+	if open := strings.IndexByte(token, '<'); open >= 0 {
+		return token[1:open]
+	}
+	return token[1:]
+}
+
+// paramChecks collects the constraints of every param in pattern, keyed by
+// param name.
+func paramChecks(pattern string) map[string]func(string) bool {
+	var checks map[string]func(string) bool
+	for {
+		i := indexParam(pattern)
+		if i < 0 {
+			return checks
+		}
+		end := segmentEnd(pattern, i)
+		if pattern[i] == ':' && strings.IndexByte(pattern[i:end], '<') >= 0 {
+			name, check := parseParamToken(pattern[i:end])
+			if checks == nil {
+				checks = make(map[string]func(string) bool)
+			}
+			checks[name] = check
+		}
+		pattern = pattern[end:]
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_URL(t *testing.T) {
+	noop := func(c *Context) *Response { return nil }
+	router := NewRouter()
+	router.Add(Route{Method: "GET", Path: "/users", Name: "users.index"}, noop)
+	router.Add(Route{Method: "GET", Path: "/users/:id<int>", Name: "users.show"}, noop)
+	router.Add(Route{Method: "GET", Path: "/teams/:team/members/:member", Name: "members.show"}, noop)
+	router.Add(Route{Method: "GET", Path: "/files/*filepath", Name: "files"}, noop)
+
+	tests := []struct {
+		name    string
+		route   string
+		params  []any
+		want    string
+		wantErr error
+	}{
+		{"static", "users.index", nil, "/users", nil},
+		{"int param", "users.show", []any{"id", 42}, "/users/42", nil},
+		{"multiple params", "members.show", []any{"member", "ada", "team", "core"}, "/teams/core/members/ada", nil},
+		{"escaping", "members.show", []any{"team", "r&d", "member", "a b/c"}, "/teams/r&d/members/a%20b%2Fc", nil},
+		{"catch-all keeps slashes", "files", []any{"filepath", "docs/my file.pdf"}, "/files/docs/my%20file.pdf", nil},
+		{"unknown route", "nope", nil, "", ErrUnknownRoute},
+		{"missing param", "members.show", []any{"team", "core"}, "", ErrInvalidURLParams},
+		{"unknown param", "users.show", []any{"id", 1, "extra", 2}, "", ErrInvalidURLParams},
+		{"odd params", "users.show", []any{"id"}, "", ErrInvalidURLParams},
+		{"non-string key", "users.show", []any{1, 2}, "", ErrInvalidURLParams},
+		{"constraint violated", "users.show", []any{"id", "abc"}, "", ErrInvalidURLParams},
+		{"empty param", "members.show", []any{"team", "", "member", "ada"}, "", ErrInvalidURLParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := router.URL(tt.route, tt.params...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRouter_DuplicateName(t *testing.T) {
+	noop := func(c *Context) *Response { return nil }
+	router := NewRouter()
+	assert.NoError(t, router.Add(Route{Method: "GET", Path: "/login", Name: "login"}, noop))
+
+	// Same name for another method of the same path is fine
+	assert.NoError(t, router.Add(Route{Method: "POST", Path: "/login", Name: "login"}, noop))
+
+	err := router.Add(Route{Method: "GET", Path: "/signin", Name: "login"}, noop)
+	assert.ErrorIs(t, err, ErrRouteConflict)
+	h, _ := router.FindHandler("GET", "/signin")
+	assert.Nil(t, h, "conflicting route must not be registered")
+}
+
+func TestContext_URL(t *testing.T) {
+	noop := func(c *Context) *Response { return nil }
+	router := NewRouter()
+	router.Add(Route{Method: "GET", Path: "/users/:id", Name: "users.show"}, noop)
+
+	c := &Context{Router: router}
+	got, err := c.URL("users.show", "id", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/7", got)
+
+	_, err = (&Context{}).URL("users.show", "id", 7)
+	assert.ErrorIs(t, err, ErrUnknownRoute)
+}
+
+func TestRouter_FuncMap(t *testing.T) {
+	noop := func(c *Context) *Response { return nil }
+	router := NewRouter()
+	router.Add(Route{Method: "GET", Path: "/users/:id", Name: "users.show"}, noop)
+
+	tmpl := template.Must(template.New("t").Funcs(router.FuncMap()).Parse(`<a href="{{ url "users.show" "id" . }}">`))
+	var buf bytes.Buffer
+	assert.NoError(t, tmpl.Execute(&buf, 5))
+	assert.Equal(t, `<a href="/users/5">`, buf.String())
+}
@@ -13,21 +13,27 @@ import (
 )
 
 func (c *Context) writeResponse(code int, contentType string, body []byte) {
-	if c.Handled {
+	if c.skipWrite() {
 		return
 	}
 	c.Writer.Header().Set("Content-Type", contentType)
-	c.Writer.WriteHeader(code)
+	c.writeHeader(code)
 	_, _ = c.Writer.Write(body)
 	c.Handled = true
 }
 
 // writeErrorResponse writes standardized error response.
+// A body exceeding the route's body limit is reported as 413 instead.
 func (c *Context) writeErrorResponse(code int, message string, err error) {
 	if c.Handled {
 		return
 	}
 
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		code, message = http.StatusRequestEntityTooLarge, "Request body too large"
+	}
+
 	c.JSON(false,
 		message,
 		err.Error(),
@@ -103,17 +109,24 @@ func (c *Context) bindFormToStruct(values url.Values, dest any) error {
 	return nil
 }
 
-// setFieldValue sets a reflect.Value based on its type.
+// setFieldValue sets a reflect.Value based on its type. Numbers that do not
+// fit the field's size are rejected with an error wrapping strconv.ErrRange.
 func (c *Context) setFieldValue(field reflect.Value, value string) error {
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(value)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		intVal, err := strconv.ParseInt(value, 10, 64)
+		intVal, err := strconv.ParseInt(value, 10, field.Type().Bits())
 		if err != nil {
 			return err
 		}
 		field.SetInt(intVal)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintVal, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(uintVal)
 	case reflect.Bool:
 		boolVal, err := strconv.ParseBool(value)
 		if err != nil {
@@ -121,7 +134,7 @@ func (c *Context) setFieldValue(field reflect.Value, value string) error {
 		}
 		field.SetBool(boolVal)
 	case reflect.Float32, reflect.Float64:
-		floatVal, err := strconv.ParseFloat(value, 64)
+		floatVal, err := strconv.ParseFloat(value, field.Type().Bits())
 		if err != nil {
 			return err
 		}
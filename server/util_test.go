@@ -26,6 +26,13 @@ func TestSetFieldValue(t *testing.T) {
 		{"string", reflect.ValueOf(new(string)).Elem(), "hello", "hello", false},
 		{"int", reflect.ValueOf(new(int64)).Elem(), "42", int64(42), false},
 		{"int_fail", reflect.ValueOf(new(int64)).Elem(), "notanint", nil, true},
+		{"uint", reflect.ValueOf(new(uint)).Elem(), "7", uint(7), false},
+		{"uint_fail", reflect.ValueOf(new(uint)).Elem(), "-7", nil, true},
+		{"int8", reflect.ValueOf(new(int8)).Elem(), "-128", int8(-128), false},
+		{"int8_overflow", reflect.ValueOf(new(int8)).Elem(), "200", nil, true},
+		{"uint8", reflect.ValueOf(new(uint8)).Elem(), "255", uint8(255), false},
+		{"uint8_overflow", reflect.ValueOf(new(uint8)).Elem(), "300", nil, true},
+		{"float32_overflow", reflect.ValueOf(new(float32)).Elem(), "1e39", nil, true},
 		{"bool_true", reflect.ValueOf(new(bool)).Elem(), "true", true, false},
 		{"bool_false", reflect.ValueOf(new(bool)).Elem(), "false", false, false},
 		{"bool_fail", reflect.ValueOf(new(bool)).Elem(), "oops", nil, true},
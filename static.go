@@ -0,0 +1,170 @@
+package onestrike
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/AscendingHeavens/onestrike/v2/server"
+)
+
+// StaticConfig configures how files are served by StaticWithConfig.
+type StaticConfig struct {
+	// FS holds the files to serve, e.g. os.DirFS("public") or an embed.FS.
+	FS fs.FS
+
+	// Index is the file served for a directory, "index.html" if empty.
+	Index string
+
+	// Browse lists the contents of directories without an index file.
+	// Otherwise such directories answer 404.
+	Browse bool
+}
+
+// Static serves the files of directory dir under prefix:
+//
+//	app.Static("/assets", "./public") // GET /assets/css/app.css -> ./public/css/app.css
+//
+// See StaticWithConfig.
+func (s *Server) Static(prefix, dir string) {
+	s.StaticWithConfig(prefix, StaticConfig{FS: os.DirFS(dir)})
+}
+
+// StaticFS serves the files of fsys under prefix, e.g. assets embedded with
+// go:embed. Use fs.Sub to serve a subdirectory of an embed.FS:
+//
+//	//go:embed web/dist
+//	var dist embed.FS
+//	sub, _ := fs.Sub(dist, "web/dist")
+//	app.StaticFS("/", sub)
+//
+// See StaticWithConfig.
+func (s *Server) StaticFS(prefix string, fsys fs.FS) {
+	s.StaticWithConfig(prefix, StaticConfig{FS: fsys})
+}
+
+// StaticWithConfig serves files under prefix as configured by cfg. GET and
+// HEAD requests are answered with the file's Content-Type, based on its
+// extension, and Last-Modified; conditional and range requests are honored.
+// Request paths are cleaned first, so "../" can never leave cfg.FS.
+// Directories redirect to their path with a trailing slash and serve their
+// index file, or a listing if cfg.Browse is set; anything else answers 404.
+func (s *Server) StaticWithConfig(prefix string, cfg StaticConfig) {
+	handler := staticHandler(cfg)
+	for _, p := range mountPatterns(strings.TrimSuffix(prefix, "/")) {
+		s.GET(p, handler)
+	}
+}
+
+// Static serves the files of directory dir under the group's prefix joined
+// with prefix. See Server.Static.
+func (g *Group) Static(prefix, dir string) {
+	g.StaticWithConfig(prefix, StaticConfig{FS: os.DirFS(dir)})
+}
+
+// StaticFS serves the files of fsys under the group's prefix joined with
+// prefix. See Server.StaticFS.
+func (g *Group) StaticFS(prefix string, fsys fs.FS) {
+	g.StaticWithConfig(prefix, StaticConfig{FS: fsys})
+}
+
+// StaticWithConfig serves files under the group's prefix joined with prefix.
+// See Server.StaticWithConfig.
+func (g *Group) StaticWithConfig(prefix string, cfg StaticConfig) {
+	handler := staticHandler(cfg)
+	for _, p := range g.mountPatterns(prefix) {
+		g.GET(p, handler)
+	}
+}
+
+// staticHandler serves the file named by the catch-all param from cfg.FS.
+func staticHandler(cfg StaticConfig) server.HandlerFunc {
+	index := cfg.Index
+	if index == "" {
+		index = "index.html"
+	}
+
+	return func(c *server.Context) *server.Response {
+		// Cleaning a rooted path resolves every "..", so name stays inside FS
+		name := strings.TrimPrefix(path.Clean("/"+c.Param("*")), "/")
+		if name == "" {
+			name = "."
+		}
+
+		info, err := fs.Stat(cfg.FS, name)
+		if err != nil {
+			return c.ErrorJSON("File not found", nil, http.StatusNotFound)
+		}
+
+		if info.IsDir() {
+			if !strings.HasSuffix(c.Request.URL.Path, "/") {
+				u := *c.Request.URL
+				u.Path += "/"
+				u.RawPath = ""
+				return c.Redirect(http.StatusMovedPermanently, u.RequestURI())
+			}
+
+			indexName := path.Join(name, index)
+			if indexInfo, err := fs.Stat(cfg.FS, indexName); err == nil && !indexInfo.IsDir() {
+				name, info = indexName, indexInfo
+			} else if cfg.Browse {
+				return listDirectory(c, cfg.FS, name)
+			} else {
+				return c.ErrorJSON("File not found", nil, http.StatusNotFound)
+			}
+		}
+
+		return serveFile(c, cfg.FS, name, info)
+	}
+}
+
+// serveFile writes the file with http.ServeContent, which sets Content-Type
+// and Last-Modified and answers conditional and range requests.
+func serveFile(c *server.Context, fsys fs.FS, name string, info fs.FileInfo) *server.Response {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return c.ErrorJSON("File not found", nil, http.StatusNotFound)
+	}
+	defer f.Close()
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return c.ErrorJSON("Failed to read file", nil, http.StatusInternalServerError)
+		}
+		content = bytes.NewReader(data)
+	}
+
+	return server.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, info.Name(), info.ModTime(), content)
+	}))(c)
+}
+
+// listDirectory writes a minimal HTML listing of the directory name.
+func listDirectory(c *server.Context, fsys fs.FS, name string) *server.Response {
+	entries, err := fs.ReadDir(fsys, name)
+	if err != nil {
+		return c.ErrorJSON("Failed to read directory", nil, http.StatusInternalServerError)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("<!doctype html>\n<pre>\n")
+	for _, entry := range entries {
+		entryName := entry.Name()
+		if entry.IsDir() {
+			entryName += "/"
+		}
+		link := url.URL{Path: entryName}
+		fmt.Fprintf(&buf, "<a href=\"%s\">%s</a>\n", html.EscapeString(link.String()), html.EscapeString(entryName))
+	}
+	buf.WriteString("</pre>\n")
+	return c.HTML(http.StatusOK, buf.String())
+}
@@ -0,0 +1,124 @@
+package onestrike
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticFS(t *testing.T) {
+	modTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"index.html":      {Data: []byte("<h1>home</h1>"), ModTime: modTime},
+		"css/app.css":     {Data: []byte("body{}"), ModTime: modTime},
+		"js/app.js":       {Data: []byte("console.log(1)"), ModTime: modTime},
+		"docs/readme.txt": {Data: []byte("docs"), ModTime: modTime},
+	}
+
+	s := New()
+	s.StaticFS("/assets", fsys)
+	s.StaticWithConfig("/browse", StaticConfig{FS: fsys, Browse: true})
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+		body        string
+		location    string
+	}{
+		{"css", "/assets/css/app.css", http.StatusOK, "text/css; charset=utf-8", "body{}", ""},
+		{"js", "/assets/js/app.js", http.StatusOK, "text/javascript; charset=utf-8", "console.log(1)", ""},
+		{"index", "/assets/", http.StatusOK, "text/html; charset=utf-8", "<h1>home</h1>", ""},
+		{"directory redirect", "/assets?x=1", http.StatusMovedPermanently, "", "", "/assets/?x=1"},
+		{"no index", "/assets/docs/", http.StatusNotFound, "application/json", "", ""},
+		{"missing", "/assets/missing.js", http.StatusNotFound, "application/json", "", ""},
+		{"traversal", "/assets/../static_test.go", http.StatusNotFound, "application/json", "", ""},
+		{"encoded traversal", "/assets/%2e%2e/%2e%2e/go.mod", http.StatusNotFound, "application/json", "", ""},
+		{"listing", "/browse/docs/", http.StatusOK, "text/html", `<a href="readme.txt">readme.txt</a>`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"))
+			}
+			if tt.body != "" {
+				assert.Contains(t, rec.Body.String(), tt.body)
+			}
+			if tt.location != "" {
+				assert.Equal(t, tt.location, rec.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestStaticFS_ConditionalRequests(t *testing.T) {
+	modTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := New()
+	s.StaticFS("/", fstest.MapFS{"app.css": {Data: []byte("body{}"), ModTime: modTime}})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/app.css", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Sat, 01 Mar 2025 12:00:00 GMT", rec.Header().Get("Last-Modified"))
+
+	req := httptest.NewRequest(http.MethodGet, "/app.css", nil)
+	req.Header.Set("If-Modified-Since", "Sat, 01 Mar 2025 12:00:00 GMT")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	// HEAD gets the headers without the body
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/app.css", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "6", rec.Header().Get("Content-Length"))
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestStatic_Directory(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("hello"), 0o644))
+
+	s := New()
+	api := s.Group("/api")
+	api.Static("/files", dir)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/files/hello.txt", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "hello", rec.Body.String())
+	assert.NotEmpty(t, rec.Header().Get("Last-Modified"))
+}
+
+func TestGroup_StaticAtGroupRoot(t *testing.T) {
+	s := New()
+	s.Group("/docs").StaticWithConfig("/", StaticConfig{FS: fstest.MapFS{
+		"index.html": {Data: []byte("<h1>docs</h1>")},
+		"guide.txt":  {Data: []byte("guide")},
+	}})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/docs/", rec.Header().Get("Location"))
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+	assert.Equal(t, "<h1>docs</h1>", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/guide.txt", nil))
+	assert.Equal(t, "guide", rec.Body.String())
+}
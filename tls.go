@@ -15,6 +15,13 @@ var listenAndServeTLS = func(addr, certFile, keyFile string, h http.Handler) err
 	return http.ListenAndServeTLS(addr, certFile, keyFile, h)
 }
 
+// serveTLS is a package-level variable that wraps (*http.Server).ListenAndServeTLS
+// for dependency injection during testing, so StartAutoTLS can be tested
+// without binding port 443.
+var serveTLS = func(server *http.Server) error {
+	return server.ListenAndServeTLS("", "")
+}
+
 // logFatal is a package-level variable that wraps log.Fatal for dependency
 // injection during testing. This allows tests to capture fatal errors without
 // actually terminating the test process.
@@ -39,6 +46,11 @@ var logFatal = func(v ...interface{}) {
 //	server := &Server{}
 //	server.StartTLS(":443", "/path/to/cert.pem", "/path/to/key.pem")
 func (s *Server) StartTLS(addr, certFile, keyFile string) {
+	if err := s.Err(); err != nil {
+		logFatal(err)
+		return
+	}
+	s.compose()
 	log.Printf("Starting server with TLS on %s", addr)
 	if err := listenAndServeTLS(addr, certFile, keyFile, s); err != nil {
 		logFatal(err)
@@ -52,7 +64,7 @@ func (s *Server) StartTLS(addr, certFile, keyFile string) {
 // This method assumes the server is already configured with TLS settings and
 // will call log.Fatal if the server fails to start.
 func (s *Server) startTLSServer(server *http.Server) {
-	logFatal(server.ListenAndServeTLS("", ""))
+	logFatal(serveTLS(server))
 }
 
 // StartAutoTLS starts the server with automatic TLS certificate management using Let's Encrypt.
@@ -110,6 +122,12 @@ func (s *Server) StartAutoTLS(domain string) {
 //	mockStarter := &MockTLSStarter{...}
 //	server.StartAutoTLSWithStarter("example.com", mockStarter)
 func (s *Server) StartAutoTLSWithStarter(domain string, starter TLSStarter) {
+	if err := s.Err(); err != nil {
+		logFatal(err)
+		return
+	}
+	s.compose()
+
 	manager := &autocert.Manager{
 		Cache:      autocert.DirCache("certs"),
 		Prompt:     autocert.AcceptTOS,
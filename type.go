@@ -2,6 +2,8 @@ package onestrike
 
 import (
 	"net/http"
+	"sync"
+	"sync/atomic"
 
 	"github.com/AscendingHeavens/onestrike/v2/middleware"
 	"github.com/AscendingHeavens/onestrike/v2/server"
@@ -23,9 +25,83 @@ type Server struct {
 	// incoming request path matches the provided pattern.
 	// For example, you might apply authentication middleware only for
 	// `/api/*` routes.
+	//
+	// Both slices are appended to under mu until the server starts serving,
+	// and never change afterwards, so requests read them without locking.
 	conditionalMiddleware []middleware.ConditionalMiddleware
+
+	// HandleMethodNotAllowed makes the server answer 405 Method Not Allowed,
+	// with an Allow header listing the registered methods, when the path
+	// matches a route but the method does not. Enabled by New(); set it to
+	// false to answer such requests with a plain 404 instead.
+	HandleMethodNotAllowed bool
+
+	// NotFound is called when no route matches the request. Like any route
+	// handler it runs behind the global and matching conditional middleware,
+	// so unmatched requests are logged and get CORS headers. If nil, a 404
+	// JSON Response is returned.
+	NotFound server.HandlerFunc
+
+	// MethodNotAllowed is called when the path matches a route but the method
+	// does not, and HandleMethodNotAllowed is enabled. The Allow header is set
+	// before it runs. If nil, a 405 JSON Response is returned.
+	MethodNotAllowed server.HandlerFunc
+
+	// RedirectTrailingSlash redirects requests whose path only differs from
+	// a registered route by a trailing slash, e.g. "/ping/" to "/ping".
+	// GET and HEAD requests get a 301, other methods a 308.
+	RedirectTrailingSlash bool
+
+	// RedirectCleanPath redirects requests with unclean paths such as
+	// "//ping" or "/api/v1/../ping" to their cleaned form if that matches
+	// a registered route.
+	RedirectCleanPath bool
+
+	// RedirectCaseInsensitive matches paths ignoring ASCII letter case in
+	// static segments and redirects to the registered casing, e.g.
+	// "/Users/42" to "/users/42".
+	RedirectCaseInsensitive bool
+
+	// ConflictPolicy controls how route conflicts detected at registration
+	// (duplicate or ambiguous routes) are reported. Defaults to ConflictPanic.
+	ConflictPolicy ConflictPolicy
+
+	// routeErrors collects registration errors under ConflictError.
+	routeErrors []error
+
+	// hosts holds the routers of Host groups, literal patterns first. The
+	// slice is never modified: Host swaps in a new one under mu, so requests
+	// read it without locking.
+	hosts atomic.Pointer[[]*hostRouter]
+
+	// routes holds every registered route by router, method and pattern.
+	// It is guarded by mu, along with routeErrors, so routes can be added
+	// and removed while serving.
+	routes map[routeKey]*routeEntry
+	mu     sync.Mutex
+
+	// composeOnce guards the composition of route middleware chains, and
+	// serving is set once they have been composed.
+	composeOnce sync.Once
+	serving     atomic.Bool
+
+	// contexts pools the Contexts of finished requests for reuse.
+	contexts sync.Pool
 }
 
+// ConflictPolicy selects how the Server reports route conflicts.
+type ConflictPolicy int
+
+const (
+	// ConflictPanic panics as soon as a conflicting route is registered.
+	ConflictPanic ConflictPolicy = iota
+
+	// ConflictError skips the conflicting route and records the error. All
+	// recorded errors are returned by Server.Err, and Start refuses to serve
+	// while there are any.
+	ConflictError
+)
+
 // Group represents a collection of routes that share a common path prefix
 // and middleware stack. Useful for organizing related endpoints like `/api/v1/*`.
 type Group struct {
@@ -40,6 +116,16 @@ type Group struct {
 	// every route registered within this group, in addition to any
 	// global or conditional middleware from the Server.
 	Middlewares []middleware.Middleware
+
+	// host is the Host pattern the group is bound to, if any.
+	host string
+
+	// router receives the group's routes. If nil, the Server's router is used.
+	router *server.Router
+
+	// parent is the group this one was created from, if any. Its middleware
+	// runs before this group's own.
+	parent *Group
 }
 
 // Context is an alias to server.Context, which wraps the request and response
@@ -50,6 +136,10 @@ type Context = server.Context
 // from every handler function. Encoded as JSON and written to the client.
 type Response = server.Response
 
+// Route is an alias to server.Route, describing a registered route as
+// returned by Server.Routes.
+type Route = server.Route
+
 // Middleware is an alias to middleware.Middleware, representing a function
 // that wraps and modifies a HandlerFunc, similar to how middleware works
 // in frameworks like Express or Fiber.
@@ -0,0 +1,273 @@
+package onestrike
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AscendingHeavens/onestrike/v2/server"
+)
+
+// VersionConfig tells Versions where clients ask for an API version. The
+// sources are checked in field order and the first one present wins.
+// Versions are compared without a leading "v", so "v2" and "2" are the same.
+type VersionConfig struct {
+	// Header is a request header carrying the version, e.g. "X-API-Version".
+	Header string
+
+	// Vendor matches vendor media types in the Accept header: with "acme",
+	// "Accept: application/vnd.acme.v2+json" asks for version 2.
+	Vendor string
+
+	// MediaParam is a media type parameter in the Accept header: with
+	// "version", "Accept: application/json; version=2" asks for version 2.
+	MediaParam string
+
+	// Query is a query parameter carrying the version, e.g. "api-version".
+	Query string
+
+	// Default is the version served when the request names none. If empty,
+	// the version registered last for the route is served.
+	Default string
+}
+
+// Versions registers routes that exist in several API versions. Each
+// method and path is registered once on the underlying Server or Group and
+// dispatches to the handler of the version the request asks for:
+//
+//	api := app.Versions(onestrike.VersionConfig{Header: "X-API-Version", Vendor: "acme", Default: "2"})
+//	api.Version("1", onestrike.Deprecated(deprecatedAt), onestrike.Sunset(sunsetAt)).
+//		GET("/users/:id", ShowUserV1)
+//	api.Version("2").GET("/users/:id", ShowUserV2)
+//
+// Requests for a version the route does not have get a 400 Response listing
+// the supported versions.
+type Versions struct {
+	cfg    VersionConfig
+	group  *Group
+	mu     sync.Mutex
+	routes map[string]*versionedRoute // by method and path
+}
+
+// VersionOption configures one API version.
+type VersionOption func(*apiVersion)
+
+// apiVersion is one version of an API and the headers it adds to responses.
+type apiVersion struct {
+	name        string
+	deprecation time.Time
+	sunset      time.Time
+}
+
+// Deprecated marks the version as deprecated since at. Its responses carry a
+// "Deprecation" header (RFC 9745).
+func Deprecated(at time.Time) VersionOption {
+	return func(v *apiVersion) {
+		v.deprecation = at
+	}
+}
+
+// Sunset announces that the version stops being served at at. Its responses
+// carry a "Sunset" header (RFC 8594).
+func Sunset(at time.Time) VersionOption {
+	return func(v *apiVersion) {
+		v.sunset = at
+	}
+}
+
+// VersionGroup registers the handlers of one API version.
+type VersionGroup struct {
+	versions *Versions
+	version  *apiVersion
+}
+
+// Versions returns a registry of versioned routes on the server.
+func (s *Server) Versions(cfg VersionConfig) *Versions {
+	return s.Group("").Versions(cfg)
+}
+
+// Versions returns a registry of versioned routes below the group's prefix,
+// behind the group's middleware.
+func (g *Group) Versions(cfg VersionConfig) *Versions {
+	return &Versions{cfg: cfg, group: g, routes: make(map[string]*versionedRoute)}
+}
+
+// Version returns the group of routes for version name.
+func (vs *Versions) Version(name string, opts ...VersionOption) *VersionGroup {
+	v := &apiVersion{name: normalizeVersion(name)}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return &VersionGroup{versions: vs, version: v}
+}
+
+// Handle registers handler as the version's implementation of method and
+// path. Middleware given with WithMiddleware applies to this version only;
+// the other options apply to the route shared by all versions and are taken
+// from the first version registering it.
+func (vg *VersionGroup) Handle(method, path string, handler HandlerFunc, opts ...RouteOption) {
+	cfg := newRouteConfig(opts)
+	handler = vg.version.headers(applyMiddleware(handler, cfg.middlewares))
+
+	vs := vg.versions
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	key := method + " " + path
+	if vr, ok := vs.routes[key]; ok {
+		vr.add(vg.version.name, handler)
+		return
+	}
+
+	// The route may be served as soon as it is added, so it must already
+	// have a version; it is only kept if it could be registered.
+	vr := &versionedRoute{cfg: vs.cfg}
+	vr.add(vg.version.name, handler)
+	g := vs.group
+	if g.Server.addRoute(g.target(), g.route(cfg, method, path), vr.serve, g, nil) == nil {
+		vs.routes[key] = vr
+	}
+}
+
+// Convenience methods for each HTTP method.
+func (vg *VersionGroup) GET(path string, handler HandlerFunc, opts ...RouteOption) {
+	vg.Handle(http.MethodGet, path, handler, opts...)
+}
+func (vg *VersionGroup) POST(path string, handler HandlerFunc, opts ...RouteOption) {
+	vg.Handle(http.MethodPost, path, handler, opts...)
+}
+func (vg *VersionGroup) PUT(path string, handler HandlerFunc, opts ...RouteOption) {
+	vg.Handle(http.MethodPut, path, handler, opts...)
+}
+func (vg *VersionGroup) PATCH(path string, handler HandlerFunc, opts ...RouteOption) {
+	vg.Handle(http.MethodPatch, path, handler, opts...)
+}
+func (vg *VersionGroup) DELETE(path string, handler HandlerFunc, opts ...RouteOption) {
+	vg.Handle(http.MethodDelete, path, handler, opts...)
+}
+
+// headers wraps next so the version's deprecation headers are set on every
+// response.
+func (v *apiVersion) headers(next HandlerFunc) HandlerFunc {
+	if v.deprecation.IsZero() && v.sunset.IsZero() {
+		return next
+	}
+	return func(c *Context) *Response {
+		if !v.deprecation.IsZero() {
+			c.Writer.Header().Set("Deprecation", "@"+strconv.FormatInt(v.deprecation.Unix(), 10))
+		}
+		if !v.sunset.IsZero() {
+			c.Writer.Header().Set("Sunset", v.sunset.UTC().Format(http.TimeFormat))
+		}
+		return next(c)
+	}
+}
+
+// versionedRoute dispatches one method and path to its versions.
+type versionedRoute struct {
+	cfg      VersionConfig
+	mu       sync.RWMutex
+	names    []string // in registration order
+	handlers map[string]HandlerFunc
+}
+
+// add registers the handler for version name, replacing any previous one.
+func (vr *versionedRoute) add(name string, handler HandlerFunc) {
+	vr.mu.Lock()
+	defer vr.mu.Unlock()
+	if vr.handlers == nil {
+		vr.handlers = make(map[string]HandlerFunc)
+	}
+	if _, ok := vr.handlers[name]; !ok {
+		vr.names = append(vr.names, name)
+	}
+	vr.handlers[name] = handler
+}
+
+// serve runs the handler of the requested version, or of the default one.
+func (vr *versionedRoute) serve(c *server.Context) *server.Response {
+	requested := vr.cfg.requested(c)
+
+	vr.mu.RLock()
+	name := requested
+	if name == "" {
+		name = vr.cfg.Default
+		if name == "" {
+			name = vr.names[len(vr.names)-1]
+		}
+	}
+	handler, ok := vr.handlers[normalizeVersion(name)]
+	supported := vr.names
+	vr.mu.RUnlock()
+
+	if !ok {
+		return c.ErrorJSON("Unsupported API version", map[string]any{
+			"requested": name,
+			"supported": supported,
+		}, http.StatusBadRequest)
+	}
+	return handler(c)
+}
+
+// requested returns the version the request asks for, or "" if none, and
+// adds the request headers the response depends on to Vary.
+func (cfg VersionConfig) requested(c *server.Context) string {
+	r := c.Request
+	useAccept := cfg.Vendor != "" || cfg.MediaParam != ""
+	if cfg.Header != "" {
+		c.Writer.Header().Add("Vary", cfg.Header)
+	}
+	if useAccept {
+		c.Writer.Header().Add("Vary", "Accept")
+	}
+
+	if cfg.Header != "" {
+		if v := r.Header.Get(cfg.Header); v != "" {
+			return normalizeVersion(v)
+		}
+	}
+	if useAccept {
+		if v := cfg.fromAccept(r.Header.Get("Accept")); v != "" {
+			return v
+		}
+	}
+	if cfg.Query != "" {
+		if v := r.URL.Query().Get(cfg.Query); v != "" {
+			return normalizeVersion(v)
+		}
+	}
+	return ""
+}
+
+// fromAccept extracts a version from the media types of an Accept header.
+func (cfg VersionConfig) fromAccept(accept string) string {
+	vendor := "application/vnd." + cfg.Vendor + "."
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if cfg.MediaParam != "" && params[cfg.MediaParam] != "" {
+			return normalizeVersion(params[cfg.MediaParam])
+		}
+		if cfg.Vendor != "" && strings.HasPrefix(mediaType, vendor) {
+			v, _, _ := strings.Cut(mediaType[len(vendor):], "+")
+			if v != "" {
+				return normalizeVersion(v)
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeVersion drops a leading "v" so that "v2" and "2" compare equal.
+func normalizeVersion(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) > 1 && (v[0] == 'v' || v[0] == 'V') {
+		return v[1:]
+	}
+	return v
+}
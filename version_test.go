@@ -0,0 +1,140 @@
+package onestrike
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersions_Selection(t *testing.T) {
+	s := New()
+	api := s.Group("/api").Versions(VersionConfig{
+		Header:     "X-API-Version",
+		Vendor:     "acme",
+		MediaParam: "version",
+		Query:      "api-version",
+		Default:    "1",
+	})
+	reply := func(msg string) HandlerFunc {
+		return func(c *Context) *Response {
+			return &Response{Success: true, Message: msg, Code: 200}
+		}
+	}
+	api.Version("1").GET("/users/:id", reply("v1"))
+	api.Version("v2").GET("/users/:id", reply("v2"))
+
+	tests := []struct {
+		name    string
+		url     string
+		headers map[string]string
+		want    string
+	}{
+		{"default", "/api/users/1", nil, "v1"},
+		{"header", "/api/users/1", map[string]string{"X-API-Version": "2"}, "v2"},
+		{"header with v", "/api/users/1", map[string]string{"X-API-Version": "v2"}, "v2"},
+		{"vendor media type", "/api/users/1", map[string]string{"Accept": "text/html, application/vnd.acme.v2+json"}, "v2"},
+		{"media type param", "/api/users/1", map[string]string{"Accept": "application/json; version=2"}, "v2"},
+		{"query", "/api/users/1?api-version=2", nil, "v2"},
+		{"header beats query", "/api/users/1?api-version=2", map[string]string{"X-API-Version": "1"}, "v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			var resp Response
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.want, resp.Message)
+			assert.Equal(t, []string{"X-API-Version", "Accept"}, rec.Header().Values("Vary"))
+		})
+	}
+
+	// One route is registered for all versions
+	assert.Len(t, s.Routes(), 1)
+}
+
+func TestVersions_Unsupported(t *testing.T) {
+	s := New()
+	api := s.Versions(VersionConfig{Header: "X-API-Version"})
+	api.Version("1").GET("/ping", func(c *Context) *Response {
+		return &Response{Success: true, Message: "v1", Code: 200}
+	})
+	api.Version("2").GET("/ping", func(c *Context) *Response {
+		return &Response{Success: true, Message: "v2", Code: 200}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("X-API-Version", "3")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"supported":["1","2"]`)
+
+	// Without a default, the latest registered version is served
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Contains(t, rec.Body.String(), "v2")
+}
+
+func TestVersions_ConflictingRouteIsNotKept(t *testing.T) {
+	s := New()
+	s.ConflictPolicy = ConflictError
+	reply := func(msg string) HandlerFunc {
+		return func(c *Context) *Response {
+			return &Response{Success: true, Message: msg, Code: 200}
+		}
+	}
+	s.GET("/ping", reply("plain"))
+
+	api := s.Versions(VersionConfig{Header: "X-API-Version"})
+	api.Version("1").GET("/ping", reply("v1"))
+	assert.Error(t, s.Err())
+
+	// Once the conflict is gone, a later version registers the route
+	assert.NoError(t, s.Remove(http.MethodGet, "/ping"))
+	api.Version("2").GET("/ping", reply("v2"))
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "v2")
+}
+
+func TestVersions_DeprecationAndMiddleware(t *testing.T) {
+	s := New()
+	deprecated := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	api := s.Versions(VersionConfig{Query: "v", Default: "2"})
+
+	legacy := func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) *Response {
+			c.Writer.Header().Set("X-Legacy", "1")
+			return next(c)
+		}
+	}
+	ok := func(c *Context) *Response {
+		return &Response{Success: true, Message: "ok", Code: 200}
+	}
+	api.Version("1", Deprecated(deprecated), Sunset(sunset)).GET("/items", ok, WithMiddleware(legacy))
+	api.Version("2").GET("/items", ok)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?v=1", nil))
+	assert.Equal(t, "@1735689600", rec.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Jan 2026 00:00:00 GMT", rec.Header().Get("Sunset"))
+	assert.Equal(t, "1", rec.Header().Get("X-Legacy"))
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+	assert.Empty(t, rec.Header().Get("X-Legacy"))
+}
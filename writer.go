@@ -0,0 +1,28 @@
+package onestrike
+
+import "net/http"
+
+// headResponseWriter wraps an http.ResponseWriter and discards the body.
+// It is used to answer HEAD requests with the GET handler registered for the
+// same path: status and headers are sent as usual, the body never is.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write pretends the whole body was written so handlers behave exactly as
+// they would for GET.
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// Flush forwards to the underlying writer, so streaming handlers still work.
+func (w *headResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}